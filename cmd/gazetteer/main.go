@@ -0,0 +1,142 @@
+// Command gazetteer imports a GeoNames dump, such as cities500.txt from
+// https://download.geonames.org/export/dump/, into the places table that city search uses.
+//
+//	go run ./cmd/gazetteer -file cities500.txt -admin1 admin1CodesASCII.txt
+//
+// Imports are idempotent: places already stored are replaced, so a newer dump can be loaded over
+// an older one.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/internal/repository"
+	placerepository "github.com/xoltawn/weatherhub/internal/repository/place"
+	"github.com/xoltawn/weatherhub/pkg/geonames"
+)
+
+func main() {
+	file := flag.String("file", "", "GeoNames dump to import, e.g. cities500.txt")
+	admin1 := flag.String("admin1", "", "optional admin1CodesASCII.txt, to store region names instead of codes")
+	minPopulation := flag.Int64("min-population", 0, "skip places with a smaller population")
+	batchSize := flag.Int("batch-size", 1000, "places upserted per transaction")
+	flag.Parse()
+
+	if *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using system environment variables")
+	}
+
+	var admin1Names map[string]string
+	if *admin1 != "" {
+		var err error
+		if admin1Names, err = readAdmin1Names(*admin1); err != nil {
+			log.Fatalf("Failed to read %s: %v", *admin1, err)
+		}
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("Failed to open %s: %v", *file, err)
+	}
+	defer f.Close()
+
+	db, err := repository.InitDB(os.Getenv("DB_URL"))
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	repo := placerepository.New(db)
+
+	ctx := context.Background()
+	reader := geonames.NewReader(f)
+	batch := make([]domain.Place, 0, *batchSize)
+	imported, skipped := 0, 0
+
+	flush := func() {
+		if err := repo.Upsert(ctx, batch); err != nil {
+			log.Fatalf("Failed to import places: %v", err)
+		}
+		imported += len(batch)
+		batch = batch[:0]
+		log.Printf("Imported %d places", imported)
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", *file, err)
+		}
+
+		if record.FeatureClass != geonames.FeatureClassPopulatedPlace || record.Population < *minPopulation {
+			skipped++
+			continue
+		}
+
+		batch = append(batch, toPlace(record, admin1Names))
+		if len(batch) == *batchSize {
+			flush()
+		}
+	}
+	if len(batch) > 0 {
+		flush()
+	}
+
+	log.Printf("Import completed: %d places imported, %d skipped", imported, skipped)
+}
+
+func readAdmin1Names(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return geonames.ReadAdmin1Names(f)
+}
+
+// toPlace converts a GeoNames record, indexing it by its name, ASCII name and alternate names.
+func toPlace(record *geonames.Place, admin1Names map[string]string) domain.Place {
+	admin1 := record.Admin1Code
+	if name, ok := admin1Names[record.Admin1Key()]; ok {
+		admin1 = name
+	}
+
+	var names []domain.PlaceName
+	seen := make(map[string]bool)
+	for _, name := range append([]string{record.Name, record.ASCIIName}, record.AlternateNames...) {
+		name = domain.NormalizeCityName(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, domain.PlaceName{PlaceID: record.ID, Name: name})
+	}
+
+	return domain.Place{
+		ID:         record.ID,
+		Name:       record.Name,
+		ASCIIName:  record.ASCIIName,
+		Country:    strings.ToLower(record.Country),
+		Admin1:     admin1,
+		Admin2:     record.Admin2Code,
+		Coord:      domain.Coordinates{Lat: record.Lat, Lon: record.Lon},
+		Population: record.Population,
+		Timezone:   record.Timezone,
+		Names:      names,
+	}
+}
@@ -2,32 +2,59 @@ package main
 
 import (
 	"context"
+	"expvar"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/joho/godotenv"
 	"github.com/redis/go-redis/v9"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 	_ "github.com/xoltawn/weatherhub/docs"
 	"github.com/xoltawn/weatherhub/internal/api/handler"
+	"github.com/xoltawn/weatherhub/internal/api/middleware"
+	"github.com/xoltawn/weatherhub/internal/domain"
 	"github.com/xoltawn/weatherhub/internal/repository"
+	apikeyrepository "github.com/xoltawn/weatherhub/internal/repository/apikey"
+	cacheinvalidationrepository "github.com/xoltawn/weatherhub/internal/repository/cacheinvalidation"
+	cityrepository "github.com/xoltawn/weatherhub/internal/repository/city"
+	forecastrepository "github.com/xoltawn/weatherhub/internal/repository/forecast"
+	placerepository "github.com/xoltawn/weatherhub/internal/repository/place"
+	watchlistrepository "github.com/xoltawn/weatherhub/internal/repository/watchlist"
 	weatherrepository "github.com/xoltawn/weatherhub/internal/repository/weather"
 	"github.com/xoltawn/weatherhub/internal/service"
+	"github.com/xoltawn/weatherhub/pkg/httputil"
+	"github.com/xoltawn/weatherhub/pkg/leader"
+	"github.com/xoltawn/weatherhub/pkg/metar"
+	"github.com/xoltawn/weatherhub/pkg/nws"
+	"github.com/xoltawn/weatherhub/pkg/openmeteo"
 	"github.com/xoltawn/weatherhub/pkg/openweathermap"
+	"github.com/xoltawn/weatherhub/pkg/resilience"
 )
 
 // @title WeatherHub API
 // @version 1.0
 // @description This is a weather data server.
 // @BasePath /api/v1
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Type "Bearer" followed by a space and the JWT.
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
 func main() {
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found, using system environment variables")
@@ -38,12 +65,70 @@ func main() {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
+	owmOpts := []httputil.Option{
+		httputil.WithTimeout(getEnvDuration("OPEN_WEATHER_MAP_TIMEOUT", httputil.DefaultTimeout)),
+	}
+	if proxy := os.Getenv("OPEN_WEATHER_MAP_PROXY_URL"); proxy != "" {
+		proxyURL, err := url.Parse(proxy)
+		if err != nil {
+			log.Fatalf("Invalid OPEN_WEATHER_MAP_PROXY_URL: %v", err)
+		}
+		owmOpts = append(owmOpts, httputil.WithProxy(proxyURL))
+	}
+
 	owmCli := openweathermap.NewOpenWeatherProvider(
 		os.Getenv("OPEN_WEATHER_MAP_API_KEY"),
 		os.Getenv("OPEN_WEATHER_MAP_BASE_URL"),
 		validator.New(),
+		owmOpts...,
+	)
+
+	openMeteoCli := openmeteo.NewOpenMeteoProvider(
+		os.Getenv("OPEN_METEO_FORECAST_URL"),
+		os.Getenv("OPEN_METEO_GEOCODING_URL"),
+		validator.New(),
+		httputil.WithTimeout(getEnvDuration("OPEN_METEO_TIMEOUT", httputil.DefaultTimeout)),
 	)
 
+	geocoder := openmeteo.NewGeocoder(os.Getenv("OPEN_METEO_GEOCODING_URL"), validator.New())
+
+	nwsCli := nws.NewNWSProvider(
+		os.Getenv("NWS_BASE_URL"),
+		geocoder,
+		httputil.WithTimeout(getEnvDuration("NWS_TIMEOUT", httputil.DefaultTimeout)),
+		httputil.WithUserAgent(getEnv("NWS_USER_AGENT", httputil.DefaultUserAgent)),
+	)
+
+	metarCli := metar.NewMETARProvider(
+		os.Getenv("METAR_BASE_URL"),
+		getEnvDuration("METAR_MAX_AGE", metar.DefaultMaxAge),
+		geocoder,
+		httputil.WithTimeout(getEnvDuration("METAR_TIMEOUT", httputil.DefaultTimeout)),
+	)
+
+	breakers := map[string]*resilience.Breaker{}
+	weatherProvider := newWeatherProvider(
+		map[string]domain.WeatherProvider{
+			openweathermap.ProviderName: owmCli,
+			openmeteo.ProviderName:      openMeteoCli,
+			nws.ProviderName:            nwsCli,
+			metar.ProviderName:          metarCli,
+		},
+		strings.Split(getEnv("WEATHER_PROVIDERS", openweathermap.ProviderName), ","),
+		breakers,
+	)
+
+	owmForecastCli := openweathermap.NewOpenWeatherForecastProvider(
+		os.Getenv("OPEN_WEATHER_MAP_API_KEY"),
+		os.Getenv("OPEN_WEATHER_MAP_FORECAST_URL"),
+		validator.New(),
+		owmOpts...,
+	)
+	breakers["openweathermap_forecast"] = newBreaker("openweathermap_forecast")
+	forecastProvider := resilience.NewForecastProvider(owmForecastCli, retryConfig(), breakers["openweathermap_forecast"])
+
+	publishBreakerStates(breakers)
+
 	cacheTTL, cacheErr := time.ParseDuration(os.Getenv("CACHE_TTL"))
 	if cacheErr != nil {
 		log.Println("Using default ttl for cache of 1 hour")
@@ -60,25 +145,143 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	// Without Redis the server still runs, reading through to the database until Redis answers
+	// one of the breaker's probes.
+	redisBreaker := resilience.NewBreaker(resilience.BreakerConfig{
+		FailureThreshold: getEnvInt("REDIS_BREAKER_THRESHOLD", 3),
+		OpenTimeout:      getEnvDuration("REDIS_PROBE_INTERVAL", 10*time.Second),
+		OnStateChange: func(from, to resilience.State) {
+			if to == resilience.StateClosed {
+				log.Printf("redis circuit breaker: %s -> %s, caching again", from, to)
+				return
+			}
+			log.Printf("redis circuit breaker: %s -> %s, serving uncached", from, to)
+		},
+	})
+	// A lone replica has no one to elect, and runs the background jobs without Redis.
+	singleReplica := getEnvBool("SINGLE_REPLICA", false)
 	if _, err := rdb.Ping(ctx).Result(); err != nil {
-		log.Fatalf("Failed to connect to redis: %v", err)
+		log.Printf("Failed to connect to redis, starting uncached: %v", err)
+		redisBreaker.Trip()
+		if !singleReplica {
+			log.Println("Background jobs wait for leader election, which needs redis; set SINGLE_REPLICA=true to run them on this replica regardless")
+		}
+	}
+
+	jwtCfg := middleware.JWTConfig{
+		Secret:   []byte(os.Getenv("JWT_SECRET")),
+		Issuer:   os.Getenv("JWT_ISSUER"),
+		Audience: os.Getenv("JWT_AUDIENCE"),
+	}
+	if pem := os.Getenv("JWT_PUBLIC_KEY"); pem != "" {
+		jwtCfg.PublicKey, err = jwt.ParseRSAPublicKeyFromPEM([]byte(pem))
+		if err != nil {
+			log.Fatalf("Failed to parse JWT public key: %v", err)
+		}
+	}
+	if len(jwtCfg.Secret) == 0 && jwtCfg.PublicKey == nil {
+		log.Fatal("Either JWT_SECRET or JWT_PUBLIC_KEY must be set")
+	}
+
+	apiKeyService := service.NewAPIKeyService(apikeyrepository.New(db))
+
+	cacheCodec, err := weatherrepository.CodecByName(getEnv("CACHE_CODEC", "msgpack"))
+	if err != nil {
+		log.Fatalf("Invalid CACHE_CODEC: %v", err)
 	}
 
 	weatherRepo := weatherrepository.New(db)
-	cachedWeatherRepo := weatherrepository.NewCachedWeatherRepo(weatherRepo, rdb, cacheTTL)
-	weatherService := service.NewWeatherService(cachedWeatherRepo, owmCli)
+	cacheInvalidationRepo := cacheinvalidationrepository.New(db)
+	cachedWeatherRepo := weatherrepository.NewCachedWeatherRepo(weatherRepo, rdb, cacheTTL,
+		weatherrepository.WithHardTTL(getEnvDuration("CACHE_HARD_TTL", cacheTTL)),
+		weatherrepository.WithNotFoundTTL(getEnvDuration("CACHE_NOT_FOUND_TTL", 30*time.Second)),
+		weatherrepository.WithLock(getEnvDuration("CACHE_LOCK_TTL", 5*time.Second)),
+		weatherrepository.WithCodec(cacheCodec),
+		weatherrepository.WithBreaker(redisBreaker),
+		weatherrepository.WithRetryQueue(cacheInvalidationRepo),
+		weatherrepository.WithLocalCache(
+			getEnvInt("CACHE_LOCAL_SIZE", 10000),
+			getEnvDuration("CACHE_LOCAL_TTL", weatherrepository.DefaultLocalCacheTTL),
+		),
+	)
+	expvar.Publish("weather_cache", expvar.Func(func() any { return cachedWeatherRepo.Stats() }))
+	cityRepo := cityrepository.New(db)
+	placeRepo := placerepository.New(db)
+	cityService := service.NewCityService(cityRepo, placeRepo)
+	if getEnvBool("VALIDATE_CITIES", false) {
+		// Only worth enabling once a gazetteer has been imported with cmd/gazetteer.
+		weatherProvider = service.NewKnownCityProvider(placeRepo, weatherProvider)
+	}
+	weatherService := service.NewWeatherService(cachedWeatherRepo, cityRepo, weatherProvider)
+
+	forecastService := service.NewForecastService(forecastrepository.New(db), forecastProvider)
+
+	// Replicas sharing the Redis server elect one of them to run the background jobs. Without an
+	// elector, the jobs run unconditionally.
+	var elector *leader.Elector
+	var jobsLeader domain.Leader
+	if !singleReplica {
+		elector = leader.New(rdb, leader.Config{
+			Key:      getEnv("LEADER_KEY", "weatherhub:leader"),
+			LeaseTTL: getEnvDuration("LEADER_LEASE_TTL", leader.DefaultLeaseTTL),
+		})
+		jobsLeader = elector
+	}
+	publishLeadership(elector)
+
+	watchlistRepo := watchlistrepository.New(db)
+	watchlistService := service.NewWatchlistService(watchlistRepo)
+	scheduler := service.NewWatchlistScheduler(watchlistRepo, weatherService, service.WatchlistSchedulerConfig{
+		PollInterval: getEnvDuration("WATCHLIST_POLL_INTERVAL", 10*time.Second),
+		Concurrency:  getEnvInt("WATCHLIST_CONCURRENCY", 4),
+		Jitter:       getEnvDuration("WATCHLIST_JITTER", 30*time.Second),
+		RunTimeout:   getEnvDuration("WATCHLIST_RUN_TIMEOUT", time.Minute),
+		Leader:       jobsLeader,
+	})
+	replayer := service.NewCacheInvalidationReplayer(cacheInvalidationRepo, cachedWeatherRepo, service.CacheInvalidationReplayerConfig{
+		PollInterval: getEnvDuration("CACHE_RETRY_POLL_INTERVAL", 5*time.Second),
+		BatchSize:    getEnvInt("CACHE_RETRY_BATCH_SIZE", 100),
+		BaseDelay:    getEnvDuration("CACHE_RETRY_BASE_DELAY", time.Second),
+		MaxDelay:     getEnvDuration("CACHE_RETRY_MAX_DELAY", time.Minute),
+		Leader:       jobsLeader,
+	})
 
 	router := gin.Default()
 	api := router.Group("/api/v1")
 	api.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	secured := api.Group("", middleware.Authenticate(jwtCfg, apiKeyService))
+
 	weatherHandler := handler.NewWeatherHandler(weatherService)
-	weatherHandler.RegisterRoutes(api)
+	weatherHandler.RegisterRoutes(secured)
+
+	cityHandler := handler.NewCityHandler(cityService, weatherService)
+	cityHandler.RegisterRoutes(secured)
+
+	forecastHandler := handler.NewForecastHandler(forecastService)
+	forecastHandler.RegisterRoutes(secured)
+
+	watchlistHandler := handler.NewWatchlistHandler(watchlistService)
+	watchlistHandler.RegisterRoutes(secured)
+
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
+	apiKeyHandler.RegisterRoutes(secured)
+
+	secured.GET("/debug/vars", handler.RequireScope(domain.ScopeWeatherAdmin), gin.WrapH(expvar.Handler()))
+
+	// Request contexts derive from baseCtx so that in-flight upstream calls are aborted on shutdown.
+	baseCtx, cancelBase := context.WithCancel(context.Background())
+	defer cancelBase()
 
 	srv := &http.Server{
-		Addr:    ":" + os.Getenv("PORT"),
-		Handler: router,
+		Addr:        ":" + os.Getenv("PORT"),
+		Handler:     router,
+		BaseContext: func(net.Listener) context.Context { return baseCtx },
 	}
+	srv.RegisterOnShutdown(cancelBase)
+
+	go cachedWeatherRepo.Run(baseCtx)
+	go replayer.Run(baseCtx)
 
 	go func() {
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -86,6 +289,22 @@ func main() {
 		}
 	}()
 
+	electorCtx, stopElector := context.WithCancel(context.Background())
+	electorDone := make(chan struct{})
+	go func() {
+		if elector != nil {
+			elector.Run(electorCtx)
+		}
+		close(electorDone)
+	}()
+
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	schedulerDone := make(chan struct{})
+	go func() {
+		scheduler.Run(schedulerCtx)
+		close(schedulerDone)
+	}()
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
@@ -94,9 +313,124 @@ func main() {
 	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	// Stop scheduling new runs first; the runs in flight finish alongside the open requests.
+	stopScheduler()
+
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatal("Server forced to shutdown:", err)
 	}
 
+	select {
+	case <-schedulerDone:
+	case <-ctx.Done():
+		log.Println("Watchlist runs still in flight at shutdown were abandoned")
+	}
+
+	// Step down once the jobs have stopped, so that another replica takes over right away.
+	stopElector()
+	select {
+	case <-electorDone:
+	case <-ctx.Done():
+	}
+
 	log.Println("Server exiting")
 }
+
+func getEnvInt(key string, fallback int) int {
+	val, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+
+	return val
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	val, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+
+	return val
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	val, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+
+	return val
+}
+
+// newWeatherProvider chains the named providers in priority order, each behind its own retries and circuit breaker.
+func newWeatherProvider(available map[string]domain.WeatherProvider, order []string, breakers map[string]*resilience.Breaker) domain.WeatherProvider {
+	var chain []resilience.NamedProvider
+
+	for _, name := range order {
+		name = strings.TrimSpace(name)
+		provider, ok := available[name]
+		if !ok {
+			log.Fatalf("Unknown weather provider %q", name)
+		}
+
+		breakers[name] = newBreaker(name)
+
+		chain = append(chain, resilience.NamedProvider{
+			Name:     name,
+			Provider: resilience.NewProvider(provider, retryConfig(), breakers[name]),
+		})
+	}
+
+	return resilience.NewFallbackProvider(chain...)
+}
+
+func retryConfig() resilience.RetryConfig {
+	return resilience.RetryConfig{
+		MaxAttempts: getEnvInt("PROVIDER_RETRY_MAX_ATTEMPTS", 3),
+		BaseDelay:   getEnvDuration("PROVIDER_RETRY_BASE_DELAY", 200*time.Millisecond),
+		MaxDelay:    getEnvDuration("PROVIDER_RETRY_MAX_DELAY", 5*time.Second),
+	}
+}
+
+func newBreaker(name string) *resilience.Breaker {
+	return resilience.NewBreaker(resilience.BreakerConfig{
+		FailureThreshold: getEnvInt("PROVIDER_BREAKER_THRESHOLD", 5),
+		OpenTimeout:      getEnvDuration("PROVIDER_BREAKER_OPEN_TIMEOUT", 30*time.Second),
+		OnStateChange: func(from, to resilience.State) {
+			log.Printf("%s circuit breaker: %s -> %s", name, from, to)
+		},
+	})
+}
+
+// publishBreakerStates exposes the breaker states through expvar, at /debug/vars.
+func publishBreakerStates(breakers map[string]*resilience.Breaker) {
+	expvar.Publish("provider_breaker_state", expvar.Func(func() any {
+		states := make(map[string]string, len(breakers))
+		for name, breaker := range breakers {
+			states[name] = breaker.State().String()
+		}
+		return states
+	}))
+}
+
+// publishLeadership exposes whether this replica runs the background jobs through expvar, at
+// /debug/vars. A nil elector means a single replica, which always runs them.
+func publishLeadership(elector *leader.Elector) {
+	expvar.Publish("leader", expvar.Func(func() any {
+		if elector == nil {
+			return map[string]any{"single_replica": true, "leading": true}
+		}
+
+		token, leading := elector.Token()
+		return map[string]any{"id": elector.ID(), "leading": leading, "token": token}
+	}))
+}
+
+func getEnv(key, fallback string) string {
+	if val := os.Getenv(key); val != "" {
+		return val
+	}
+
+	return fallback
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/xoltawn/weatherhub/internal/domain"
+)
+
+type APIKeyHandler struct {
+	service domain.APIKeyService
+}
+
+func NewAPIKeyHandler(service domain.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{service: service}
+}
+
+func (h *APIKeyHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	keys := rg.Group("/api-keys", RequireScope(domain.ScopeWeatherAdmin))
+	{
+		keys.GET("", h.List)
+		keys.POST("", h.Create)
+		keys.DELETE("/:id", h.Revoke)
+	}
+}
+
+type CreateAPIKeyResponse struct {
+	Key    string         `json:"key"`
+	APIKey *domain.APIKey `json:"api_key"`
+}
+
+// Create godoc
+// @Summary      Create an API key
+// @Description  Issues a new API key with the given scopes. The plaintext key is only returned in this response.
+// @Tags         api-keys
+// @Accept       json
+// @Produce      json
+// @Param        request  body      object{name=string,scopes=[]string,expires_at=string}  true  "Key name, scopes and optional RFC 3339 expiry"
+// @Success      201      {object}  handler.CreateAPIKeyResponse
+// @Failure      400      {object}  map[string]string
+// @Failure      401      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /api-keys [post]
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	var input struct {
+		Name      string     `json:"name"       binding:"required,min=2,max=100"`
+		Scopes    []string   `json:"scopes"     binding:"required,min=1"`
+		ExpiresAt *time.Time `json:"expires_at"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	key, plaintext, err := h.service.Create(c.Request.Context(), input.Name, input.Scopes, input.ExpiresAt)
+	if err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, CreateAPIKeyResponse{Key: plaintext, APIKey: key})
+}
+
+// List godoc
+// @Summary      List API keys
+// @Description  Retrieve every API key, including revoked and expired ones. Plaintext keys are never returned.
+// @Tags         api-keys
+// @Produce      json
+// @Success      200  {array}   domain.APIKey
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /api-keys [get]
+func (h *APIKeyHandler) List(c *gin.Context) {
+	keys, err := h.service.List(c.Request.Context())
+	if err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+// Revoke godoc
+// @Summary      Revoke an API key
+// @Description  Permanently disables an API key by ID
+// @Tags         api-keys
+// @Param        id   path      string  true  "API key UUID"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /api-keys/{id} [delete]
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		RespondWithError(c, domain.ErrInvalidInput)
+		return
+	}
+
+	if err := h.service.Revoke(c.Request.Context(), id); err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Successfully revoked"})
+}
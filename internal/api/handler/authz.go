@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/xoltawn/weatherhub/internal/domain"
+)
+
+// RequireScope only lets a request through when the caller holds at least one of the given scopes.
+// It must run after an authentication middleware has put the caller's identity in the request context.
+func RequireScope(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity, ok := domain.IdentityFromContext(c.Request.Context())
+		if !ok {
+			RespondWithError(c, domain.ErrUnauthorized)
+			c.Abort()
+			return
+		}
+
+		if !identity.HasAnyScope(scopes...) {
+			RespondWithError(c, domain.ErrForbidden)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,70 @@
+package handler_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/xoltawn/weatherhub/internal/api/handler"
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/internal/repository/mocks"
+	"github.com/xoltawn/weatherhub/internal/service"
+)
+
+func withScopes(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if scopes != nil {
+			identity := &domain.Identity{Subject: "tester", Scopes: scopes}
+			c.Request = c.Request.WithContext(domain.ContextWithIdentity(c.Request.Context(), identity))
+		}
+		c.Next()
+	}
+}
+
+func TestWeatherHandler_RegisterRoutes_Scopes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	id := uuid.New()
+
+	tests := []struct {
+		name       string
+		scopes     []string
+		method     string
+		path       string
+		wantStatus int
+	}{
+		{name: "anonymous-read", scopes: nil, method: http.MethodGet, path: "/weather/" + id.String(), wantStatus: http.StatusUnauthorized},
+		{name: "reader-read", scopes: []string{domain.ScopeWeatherRead}, method: http.MethodGet, path: "/weather/" + id.String(), wantStatus: http.StatusOK},
+		{name: "writer-read", scopes: []string{domain.ScopeWeatherWrite}, method: http.MethodGet, path: "/weather/" + id.String(), wantStatus: http.StatusForbidden},
+		{name: "reader-current", scopes: []string{domain.ScopeWeatherRead}, method: http.MethodGet, path: "/weather/current/london", wantStatus: http.StatusForbidden},
+		{name: "reader-create", scopes: []string{domain.ScopeWeatherRead}, method: http.MethodPost, path: "/weather", wantStatus: http.StatusForbidden},
+		{name: "writer-delete", scopes: []string{domain.ScopeWeatherWrite}, method: http.MethodDelete, path: "/weather/" + id.String(), wantStatus: http.StatusForbidden},
+		{name: "admin-delete", scopes: []string{domain.ScopeWeatherAdmin}, method: http.MethodDelete, path: "/weather/" + id.String(), wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := mocks.NewWeatherRepository(t)
+			mockRepo.On("GetByID", mock.Anything, id).Return(&domain.Weather{ID: id}, nil).Maybe()
+			mockRepo.On("Delete", mock.Anything, id).Return(nil).Maybe()
+
+			h := handler.NewWeatherHandler(service.NewWeatherService(mockRepo, nil, nil))
+
+			router := gin.New()
+			h.RegisterRoutes(router.Group("", withScopes(tt.scopes...)))
+
+			// Act
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tt.method, tt.path, nil)
+			router.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
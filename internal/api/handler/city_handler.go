@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/xoltawn/weatherhub/internal/domain"
+)
+
+type CityHandler struct {
+	service        domain.CityService
+	weatherService domain.WeatherService
+}
+
+func NewCityHandler(service domain.CityService, weatherService domain.WeatherService) *CityHandler {
+	return &CityHandler{
+		service:        service,
+		weatherService: weatherService,
+	}
+}
+
+func (h *CityHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	cities := rg.Group("/cities", RequireScope(domain.ScopeWeatherRead, domain.ScopeWeatherAdmin))
+	{
+		cities.GET("", h.Find)
+		cities.GET("/search", h.Search)
+		cities.GET("/:id", h.GetByID)
+		cities.GET("/:id/weather/latest", h.GetLatestWeather)
+	}
+}
+
+// Find godoc
+// @Summary      Find cities by name
+// @Description  Lists the known cities matching a name or alias, to tell apart cities that share a name
+// @Tags         cities
+// @Produce      json
+// @Param        name     query     string  true   "City name"
+// @Param        country  query     string  false  "ISO 3166 country code"
+// @Success      200      {array}   domain.City
+// @Failure      400      {object}  map[string]string
+// @Failure      401      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /cities [get]
+func (h *CityHandler) Find(c *gin.Context) {
+	var input struct {
+		Name    string `form:"name"    binding:"required,min=2,max=50"`
+		Country string `form:"country" binding:"omitempty,iso3166_1_alpha2"`
+	}
+	if err := c.ShouldBindQuery(&input); err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	cities, err := h.service.Find(c.Request.Context(), input.Name, input.Country)
+	if err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, cities)
+}
+
+// Search godoc
+// @Summary      Search the city gazetteer
+// @Description  Autocompletes city names from the offline gazetteer without calling a weather provider. Names starting with q come first, then names resembling it; each group is ordered by population.
+// @Tags         cities
+// @Produce      json
+// @Param        q        query     string  true   "Name or name prefix"
+// @Param        country  query     string  false  "ISO 3166 country code"
+// @Param        limit    query     int     false  "Maximum number of results, up to 50"  default(10)
+// @Success      200      {array}   domain.Place
+// @Failure      400      {object}  map[string]string
+// @Failure      401      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /cities/search [get]
+func (h *CityHandler) Search(c *gin.Context) {
+	input := struct {
+		Query   string `form:"q"       binding:"required,min=2,max=50"`
+		Country string `form:"country" binding:"omitempty,iso3166_1_alpha2"`
+		Limit   int    `form:"limit"   binding:"min=1,max=50"`
+	}{
+		Limit: 10,
+	}
+	if err := c.ShouldBindQuery(&input); err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	places, err := h.service.Search(c.Request.Context(), input.Query, input.Country, input.Limit)
+	if err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, places)
+}
+
+// GetByID godoc
+// @Summary      Get city by ID
+// @Tags         cities
+// @Produce      json
+// @Param        id   path      string  true  "City UUID"
+// @Success      200  {object}  domain.City
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /cities/{id} [get]
+func (h *CityHandler) GetByID(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		RespondWithError(c, domain.ErrInvalidInput)
+		return
+	}
+
+	city, err := h.service.GetByID(c.Request.Context(), id)
+	if err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, city)
+}
+
+// GetLatestWeather godoc
+// @Summary      Get latest weather of a city
+// @Description  Retrieve the most recently fetched weather record for the city with the given ID
+// @Tags         cities
+// @Produce      json
+// @Param        id   path      string  true  "City UUID"
+// @Success      200  {object}  domain.Weather
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /cities/{id}/weather/latest [get]
+func (h *CityHandler) GetLatestWeather(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		RespondWithError(c, domain.ErrInvalidInput)
+		return
+	}
+
+	weather, err := h.weatherService.GetLatestByCityID(c.Request.Context(), id)
+	if err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, weather)
+}
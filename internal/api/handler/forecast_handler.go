@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xoltawn/weatherhub/internal/domain"
+)
+
+type ForecastHandler struct {
+	service domain.ForecastService
+}
+
+func NewForecastHandler(service domain.ForecastService) *ForecastHandler {
+	return &ForecastHandler{service: service}
+}
+
+func (h *ForecastHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	canRead := RequireScope(domain.ScopeWeatherRead, domain.ScopeWeatherAdmin)
+
+	rg.GET("/forecast/:cityName", canRead, h.Get)
+}
+
+// Get godoc
+// @Summary      Get a city forecast
+// @Description  Returns the forecast steps of the latest forecast run valid within the next hours, fetching a new run from OpenWeatherMap when the stored one is older than an hour
+// @Tags         forecast
+// @Produce      json
+// @Param        cityName  path      string  true   "City Name"
+// @Param        country   query     string  true   "ISO 3166 country code"
+// @Param        units     query     string  false  "metric or imperial"  default(metric)
+// @Param        hours     query     int     false  "Forecast horizon in hours, up to 120"  default(24)
+// @Success      200       {object}  domain.Forecast
+// @Failure      400       {object}  map[string]string
+// @Failure      401       {object}  map[string]string
+// @Failure      403       {object}  map[string]string
+// @Failure      500       {object}  map[string]string
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /forecast/{cityName} [get]
+func (h *ForecastHandler) Get(c *gin.Context) {
+	input := struct {
+		Country string      `form:"country" binding:"required,iso3166_1_alpha2"`
+		Units   domain.Unit `form:"units"   binding:"oneof=metric imperial"`
+		Hours   int         `form:"hours"   binding:"min=1,max=120"`
+	}{
+		Units: domain.Metric,
+		Hours: 24,
+	}
+
+	if err := c.ShouldBindQuery(&input); err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	result, err := h.service.GetForecast(c.Request.Context(), c.Param("cityName"), input.Country, input.Units, input.Hours)
+	if err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -3,6 +3,8 @@ package handler
 import (
 	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
@@ -36,10 +38,26 @@ func RespondWithError(c *gin.Context, err error) {
 	var statusCode int
 	var message string
 
+	var numErr *strconv.NumError
+	var timeErr *time.ParseError
+
 	switch {
+	case errors.As(err, &numErr), errors.As(err, &timeErr):
+		// Query and form values that don't parse into their field's type.
+		statusCode = http.StatusBadRequest
+		message = err.Error()
 	case errors.Is(err, domain.ErrInvalidInput):
 		statusCode = http.StatusBadRequest
 		message = err.Error()
+	case errors.Is(err, domain.ErrUnauthorized):
+		statusCode = http.StatusUnauthorized
+		message = "Missing or invalid credentials."
+	case errors.Is(err, domain.ErrForbidden):
+		statusCode = http.StatusForbidden
+		message = "You do not have permission to perform this action."
+	case errors.Is(err, domain.ErrAmbiguous):
+		statusCode = http.StatusConflict
+		message = err.Error()
 	case errors.Is(err, domain.ErrNotFound):
 		statusCode = http.StatusNotFound
 		message = "The requested resource was not found."
@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/xoltawn/weatherhub/internal/domain"
+)
+
+type WatchlistHandler struct {
+	service domain.WatchlistService
+}
+
+func NewWatchlistHandler(service domain.WatchlistService) *WatchlistHandler {
+	return &WatchlistHandler{service: service}
+}
+
+func (h *WatchlistHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	canRead := RequireScope(domain.ScopeWeatherRead, domain.ScopeWeatherAdmin)
+	isAdmin := RequireScope(domain.ScopeWeatherAdmin)
+
+	watchlist := rg.Group("/watchlist")
+	{
+		watchlist.GET("", canRead, h.GetAll)
+		watchlist.GET("/:id", canRead, h.GetByID)
+		watchlist.POST("", isAdmin, h.Create)
+		watchlist.PUT("/:id", isAdmin, h.Update)
+		watchlist.DELETE("/:id", isAdmin, h.Delete)
+	}
+}
+
+// watchlistInput is the body of create and update requests. Enabled defaults to true.
+type watchlistInput struct {
+	CityName string      `json:"cityName" binding:"required,min=2,max=50"`
+	Country  string      `json:"country"  binding:"required,iso3166_1_alpha2"`
+	Units    domain.Unit `json:"units"    binding:"required,oneof=metric imperial"`
+	Interval string      `json:"interval" binding:"omitempty,max=32"`
+	Cron     string      `json:"cron"     binding:"omitempty,max=128"`
+	Enabled  *bool       `json:"enabled"`
+}
+
+func (in *watchlistInput) entry() *domain.WatchlistEntry {
+	enabled := true
+	if in.Enabled != nil {
+		enabled = *in.Enabled
+	}
+
+	return &domain.WatchlistEntry{
+		CityName: in.CityName,
+		Country:  in.Country,
+		Unit:     in.Units,
+		Interval: in.Interval,
+		Cron:     in.Cron,
+		Enabled:  enabled,
+	}
+}
+
+// Create godoc
+// @Summary      Add a location to the watchlist
+// @Description  Schedules the weather of a city to be fetched and stored every interval (a Go duration of at least 1m, e.g. 15m) or at the times of a five-field cron expression in UTC. Set exactly one of the two.
+// @Tags         watchlist
+// @Accept       json
+// @Produce      json
+// @Param        request  body      object{cityName=string,country=string,units=string,interval=string,cron=string,enabled=boolean}  true  "Location and schedule"
+// @Success      201      {object}  domain.WatchlistEntry
+// @Failure      400      {object}  map[string]string
+// @Failure      401      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Failure      500      {object}  map[string]string
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /watchlist [post]
+func (h *WatchlistHandler) Create(c *gin.Context) {
+	var input watchlistInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	entry, err := h.service.Create(c.Request.Context(), input.entry())
+	if err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// GetAll godoc
+// @Summary      List the watchlist
+// @Tags         watchlist
+// @Produce      json
+// @Success      200  {array}   domain.WatchlistEntry
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /watchlist [get]
+func (h *WatchlistHandler) GetAll(c *gin.Context) {
+	entries, err := h.service.GetAll(c.Request.Context())
+	if err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// GetByID godoc
+// @Summary      Get a watchlist entry
+// @Tags         watchlist
+// @Produce      json
+// @Param        id   path      string  true  "Watchlist entry UUID"
+// @Success      200  {object}  domain.WatchlistEntry
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /watchlist/{id} [get]
+func (h *WatchlistHandler) GetByID(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		RespondWithError(c, domain.ErrInvalidInput)
+		return
+	}
+
+	entry, err := h.service.GetByID(c.Request.Context(), id)
+	if err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// Update godoc
+// @Summary      Update a watchlist entry
+// @Description  Replaces the location and schedule of an entry. A changed schedule, or a disabled entry being enabled, starts over from now.
+// @Tags         watchlist
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string  true  "Watchlist entry UUID"
+// @Param        request  body      object{cityName=string,country=string,units=string,interval=string,cron=string,enabled=boolean}  true  "Location and schedule"
+// @Success      200      {object}  domain.WatchlistEntry
+// @Failure      400      {object}  map[string]string
+// @Failure      401      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
+// @Failure      404      {object}  map[string]string
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /watchlist/{id} [put]
+func (h *WatchlistHandler) Update(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		RespondWithError(c, domain.ErrInvalidInput)
+		return
+	}
+
+	var input watchlistInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	entry, err := h.service.Update(c.Request.Context(), id, input.entry())
+	if err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// Delete godoc
+// @Summary      Remove a location from the watchlist
+// @Tags         watchlist
+// @Param        id   path      string  true  "Watchlist entry UUID"
+// @Success      200  {object}  map[string]string
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /watchlist/{id} [delete]
+func (h *WatchlistHandler) Delete(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		RespondWithError(c, domain.ErrInvalidInput)
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Successfully deleted"})
+}
@@ -2,10 +2,12 @@ package handler
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/pkg/errutil"
 )
 
 type WeatherHandler struct {
@@ -17,33 +19,44 @@ func NewWeatherHandler(service domain.WeatherService) *WeatherHandler {
 }
 
 func (h *WeatherHandler) RegisterRoutes(rg *gin.RouterGroup) {
+	canRead := RequireScope(domain.ScopeWeatherRead, domain.ScopeWeatherAdmin)
+	canWrite := RequireScope(domain.ScopeWeatherWrite, domain.ScopeWeatherAdmin)
+	isAdmin := RequireScope(domain.ScopeWeatherAdmin)
+
 	weather := rg.Group("/weather")
 	{
-		weather.GET("", h.GetAll)
-		weather.GET("/:id", h.GetByID)
-		weather.POST("", h.Create)
-		weather.PUT("/:id", h.Update)
-		weather.DELETE("/:id", h.Delete)
-		weather.GET("/latest/:cityName", h.GetLatest)
+		weather.GET("", canRead, h.GetAll)
+		weather.GET("/:id", canRead, h.GetByID)
+		weather.POST("", canWrite, h.Create)
+		weather.PUT("/:id", isAdmin, h.Update)
+		weather.DELETE("/:id", isAdmin, h.Delete)
+		weather.GET("/latest/:cityName", canRead, h.GetLatest)
+		weather.GET("/current/:cityName", canWrite, h.GetCurrent)
+		weather.GET("/history/:cityName", canRead, h.GetHistory)
 	}
 }
 
 // Create godoc
 // @Summary      Fetch and store weather
-// @Description  Calls OpenWeatherMap API for a city/country and saves the result to the database
+// @Description  Fetches the current weather for either a city and country or a lat/lon pair from the configured providers and saves the result to the database
 // @Tags         weather
 // @Accept       json
 // @Produce      json
-// @Param        request  body      object{cityName=string,country=string,units=string}  true  "City and Country codes"
+// @Param        request  body      object{cityName=string,country=string,lat=number,lon=number,units=string}  true  "City and country code, or coordinates"
 // @Success      201      {object}  domain.Weather
 // @Failure      400      {object}  map[string]string
+// @Failure      401      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
 // @Failure      500      {object}  map[string]string
 // @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Router       /weather [post]
 func (h *WeatherHandler) Create(c *gin.Context) {
 	var input struct {
-		CityName string      `json:"cityName" binding:"required,min=2,max=50"`
-		Country  string      `json:"country"  binding:"required,iso3166_1_alpha2"`
+		CityName string      `json:"cityName" binding:"omitempty,min=2,max=50"`
+		Country  string      `json:"country"  binding:"omitempty,iso3166_1_alpha2"`
+		Lat      *float64    `json:"lat"      binding:"omitempty,min=-90,max=90"`
+		Lon      *float64    `json:"lon"      binding:"omitempty,min=-180,max=180"`
 		Units    domain.Unit `json:"units"    binding:"required,oneof=metric imperial"`
 	}
 
@@ -52,7 +65,26 @@ func (h *WeatherHandler) Create(c *gin.Context) {
 		return
 	}
 
-	result, err := h.service.FetchAndStore(c.Request.Context(), input.CityName, input.Country, input.Units)
+	byCity := input.CityName != "" || input.Country != ""
+	byCoords := input.Lat != nil || input.Lon != nil
+
+	var (
+		result *domain.Weather
+		err    error
+	)
+
+	switch {
+	case byCity && byCoords:
+		err = errutil.Wrap(domain.ErrInvalidInput, "send either cityName and country or lat and lon, not both")
+	case byCoords && (input.Lat == nil || input.Lon == nil):
+		err = errutil.Wrap(domain.ErrInvalidInput, "lat and lon must be sent together")
+	case byCoords:
+		result, err = h.service.FetchAndStoreByCoordinates(c.Request.Context(), domain.Coordinates{Lat: *input.Lat, Lon: *input.Lon}, input.Units)
+	case input.CityName == "" || input.Country == "":
+		err = errutil.Wrap(domain.ErrInvalidInput, "cityName and country, or lat and lon, are required")
+	default:
+		result, err = h.service.FetchAndStore(c.Request.Context(), input.CityName, input.Country, input.Units)
+	}
 	if err != nil {
 		RespondWithError(c, err)
 		return
@@ -62,22 +94,78 @@ func (h *WeatherHandler) Create(c *gin.Context) {
 }
 
 // GetAll godoc
-// @Summary      List all weather records
-// @Description  Retrieve every weather record currently stored in the database
+// @Summary      List weather records
+// @Description  Lists stored weather records a page at a time. Pass the returned next_cursor as cursor to fetch the following page, keeping the other parameters unchanged.
 // @Tags         weather
 // @Produce      json
-// @Success      200  {array}   domain.Weather
-// @Failure      500  {object}  map[string]string
+// @Param        city      query     string   false  "City name as requested"
+// @Param        city_id   query     string   false  "City UUID"
+// @Param        country   query     string   false  "ISO 3166 country code"
+// @Param        unit      query     string   false  "metric or imperial"
+// @Param        from      query     string   false  "Fetched at or after, RFC 3339"
+// @Param        to        query     string   false  "Fetched before, RFC 3339"
+// @Param        min_temp  query     number   false  "Minimum temperature, in the record's unit"
+// @Param        max_temp  query     number   false  "Maximum temperature, in the record's unit"
+// @Param        sort      query     string   false  "fetched_at, temperature or city_name, prefixed by - for descending order"  default(-fetched_at)
+// @Param        limit     query     int      false  "Page size, up to 100"  default(20)
+// @Param        cursor    query     string   false  "next_cursor of the previous page"
+// @Success      200       {object}  domain.WeatherPage
+// @Failure      400       {object}  map[string]string
+// @Failure      401       {object}  map[string]string
+// @Failure      403       {object}  map[string]string
+// @Failure      500       {object}  map[string]string
 // @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Router       /weather [get]
 func (h *WeatherHandler) GetAll(c *gin.Context) {
-	weathers, err := h.service.GetAllRecords(c.Request.Context())
+	var input struct {
+		City    string      `form:"city"     binding:"omitempty,max=50"`
+		CityID  string      `form:"city_id"  binding:"omitempty,uuid"`
+		Country string      `form:"country"  binding:"omitempty,iso3166_1_alpha2"`
+		Unit    domain.Unit `form:"unit"     binding:"omitempty,oneof=metric imperial"`
+		From    *time.Time  `form:"from"     time_format:"2006-01-02T15:04:05Z07:00"`
+		To      *time.Time  `form:"to"       time_format:"2006-01-02T15:04:05Z07:00"`
+		MinTemp *float64    `form:"min_temp"`
+		MaxTemp *float64    `form:"max_temp"`
+		Sort    string      `form:"sort"`
+		Limit   int         `form:"limit"    binding:"omitempty,min=1,max=100"`
+		Cursor  string      `form:"cursor"   binding:"omitempty,max=512"`
+	}
+	if err := c.ShouldBindQuery(&input); err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	sort, err := domain.ParseWeatherSort(input.Sort)
+	if err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	query := domain.WeatherQuery{
+		CityName:       input.City,
+		Country:        input.Country,
+		Unit:           input.Unit,
+		FetchedFrom:    input.From,
+		FetchedTo:      input.To,
+		MinTemperature: input.MinTemp,
+		MaxTemperature: input.MaxTemp,
+		Sort:           sort,
+		Limit:          input.Limit,
+		Cursor:         input.Cursor,
+	}
+	if input.CityID != "" {
+		id := uuid.MustParse(input.CityID)
+		query.CityID = &id
+	}
+
+	page, err := h.service.ListRecords(c.Request.Context(), query)
 	if err != nil {
 		RespondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, weathers)
+	c.JSON(http.StatusOK, page)
 }
 
 // GetByID godoc
@@ -88,8 +176,11 @@ func (h *WeatherHandler) GetAll(c *gin.Context) {
 // @Param        id   path      string  true  "Weather UUID"
 // @Success      200  {object}  domain.Weather
 // @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
 // @Failure      404  {object}  map[string]string
 // @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Router       /weather/{id} [get]
 func (h *WeatherHandler) GetByID(c *gin.Context) {
 	idStr := c.Param("id")
@@ -119,7 +210,10 @@ func (h *WeatherHandler) GetByID(c *gin.Context) {
 // @Param        updates  body      domain.Weather  true  "Fields to update"
 // @Success      200      {object}  domain.Weather
 // @Failure      400      {object}  map[string]string
+// @Failure      401      {object}  map[string]string
+// @Failure      403      {object}  map[string]string
 // @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Router       /weather/{id} [put]
 func (h *WeatherHandler) Update(c *gin.Context) {
 	idStr := c.Param("id")
@@ -152,7 +246,10 @@ func (h *WeatherHandler) Update(c *gin.Context) {
 // @Param        id   path      string  true  "Weather UUID"
 // @Success      200  {object}  map[string]string
 // @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
 // @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Router       /weather/{id} [delete]
 func (h *WeatherHandler) Delete(c *gin.Context) {
 	idStr := c.Param("id")
@@ -172,18 +269,139 @@ func (h *WeatherHandler) Delete(c *gin.Context) {
 
 // GetLatest godoc
 // @Summary      Get latest city weather
-// @Description  Retrieve the most recently fetched weather record for a specific city
+// @Description  Retrieve the most recently fetched weather record for a city. When several cities share the name, pass a country or use /cities/{id}/weather/latest.
 // @Tags         weather
 // @Produce      json
-// @Param        cityName  path      string  true  "City Name"
+// @Param        cityName  path      string  true   "City Name"
+// @Param        country   query     string  false  "ISO 3166 country code"
 // @Success      200       {object}  domain.Weather
+// @Failure      400       {object}  map[string]string
+// @Failure      401       {object}  map[string]string
+// @Failure      403       {object}  map[string]string
 // @Failure      404       {object}  map[string]string
+// @Failure      409       {object}  map[string]string
 // @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Router       /weather/latest/{cityName} [get]
 func (h *WeatherHandler) GetLatest(c *gin.Context) {
 	cityName := c.Param("cityName")
 
-	result, err := h.service.GetLatest(c.Request.Context(), cityName)
+	var input struct {
+		Country string `form:"country" binding:"omitempty,iso3166_1_alpha2"`
+	}
+	if err := c.ShouldBindQuery(&input); err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	result, err := h.service.GetLatest(c.Request.Context(), cityName, input.Country)
+	if err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetCurrent godoc
+// @Summary      Get current city weather
+// @Description  Returns the latest stored record of a city in the requested units when it was fetched within max_age, and otherwise fetches, stores and returns a new one. Concurrent requests for the same city share one upstream call. The source field tells whether the record came from storage or the provider. Requires the weather:write scope, since it may fetch and store.
+// @Tags         weather
+// @Produce      json
+// @Param        cityName  path      string  true   "City Name"
+// @Param        country   query     string  false  "ISO 3166 country code"
+// @Param        units     query     string  false  "metric or imperial"  default(metric)
+// @Param        max_age   query     string  false  "Oldest acceptable record as a Go duration, e.g. 15m; 0 always fetches"  default(15m)
+// @Success      200       {object}  domain.CurrentWeather
+// @Failure      400       {object}  map[string]string
+// @Failure      401       {object}  map[string]string
+// @Failure      403       {object}  map[string]string
+// @Failure      404       {object}  map[string]string
+// @Failure      409       {object}  map[string]string
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /weather/current/{cityName} [get]
+func (h *WeatherHandler) GetCurrent(c *gin.Context) {
+	input := struct {
+		Country string      `form:"country" binding:"omitempty,iso3166_1_alpha2"`
+		Units   domain.Unit `form:"units"   binding:"oneof=metric imperial"`
+		MaxAge  string      `form:"max_age"`
+	}{
+		Units: domain.Metric,
+	}
+	if err := c.ShouldBindQuery(&input); err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	maxAge := domain.DefaultMaxAge
+	if input.MaxAge != "" {
+		var err error
+		if maxAge, err = time.ParseDuration(input.MaxAge); err != nil {
+			RespondWithError(c, errutil.Wrapf(domain.ErrInvalidInput, "invalid max_age %q", input.MaxAge))
+			return
+		}
+	}
+
+	result, err := h.service.GetCurrent(c.Request.Context(), c.Param("cityName"), input.Country, input.Units, maxAge)
+	if err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetHistory godoc
+// @Summary      Get city weather history
+// @Description  Returns the observations of a city fetched within [from, to), oldest first. With an interval, returns per-interval aggregates instead; intervals without observations are left out. Either way at most 1000 points are returned.
+// @Tags         weather
+// @Produce      json
+// @Param        cityName  path      string  true   "City Name"
+// @Param        country   query     string  false  "ISO 3166 country code"
+// @Param        from      query     string  false  "Window start, RFC 3339; defaults to 24 hours before to"
+// @Param        to        query     string  false  "Window end, RFC 3339; defaults to now"
+// @Param        interval  query     string  false  "Bucket width as a Go duration, e.g. 15m or 1h, at least 1m"
+// @Param        units     query     string  false  "metric or imperial"  default(metric)
+// @Success      200       {object}  domain.WeatherHistory
+// @Failure      400       {object}  map[string]string
+// @Failure      401       {object}  map[string]string
+// @Failure      403       {object}  map[string]string
+// @Failure      404       {object}  map[string]string
+// @Failure      409       {object}  map[string]string
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /weather/history/{cityName} [get]
+func (h *WeatherHandler) GetHistory(c *gin.Context) {
+	input := struct {
+		Country  string      `form:"country"  binding:"omitempty,iso3166_1_alpha2"`
+		From     time.Time   `form:"from"     time_format:"2006-01-02T15:04:05Z07:00"`
+		To       time.Time   `form:"to"       time_format:"2006-01-02T15:04:05Z07:00"`
+		Interval string      `form:"interval"`
+		Units    domain.Unit `form:"units"    binding:"oneof=metric imperial"`
+	}{
+		Units: domain.Metric,
+	}
+	if err := c.ShouldBindQuery(&input); err != nil {
+		RespondWithError(c, err)
+		return
+	}
+
+	query := domain.WeatherHistoryQuery{
+		Unit: input.Units,
+		From: input.From,
+		To:   input.To,
+	}
+	if input.Interval != "" {
+		interval, err := time.ParseDuration(input.Interval)
+		if err != nil {
+			RespondWithError(c, errutil.Wrapf(domain.ErrInvalidInput, "invalid interval %q", input.Interval))
+			return
+		}
+		query.Interval = interval
+	}
+
+	result, err := h.service.GetHistory(c.Request.Context(), c.Param("cityName"), input.Country, query)
 	if err != nil {
 		RespondWithError(c, err)
 		return
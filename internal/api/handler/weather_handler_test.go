@@ -1,10 +1,13 @@
 package handler_test
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -21,7 +24,7 @@ func TestWeatherHandler_GetByID(t *testing.T) {
 
 	mockRepo := mocks.NewWeatherRepository(t)
 
-	weatherService := service.NewWeatherService(mockRepo, nil)
+	weatherService := service.NewWeatherService(mockRepo, nil, nil)
 
 	h := handler.NewWeatherHandler(weatherService)
 
@@ -62,3 +65,218 @@ func TestWeatherHandler_GetByID(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 	})
 }
+
+type stubProvider struct {
+	coords *domain.Coordinates
+}
+
+func (p *stubProvider) GetForecast(ctx context.Context, city, country string, units domain.Unit) (*domain.WeatherData, error) {
+	return &domain.WeatherData{CityName: city, CountryCode: country, Temperature: 15}, nil
+}
+
+func (p *stubProvider) GetForecastByCoordinates(ctx context.Context, coords domain.Coordinates, units domain.Unit) (*domain.WeatherData, error) {
+	p.coords = &coords
+	return &domain.WeatherData{Temperature: 21}, nil
+}
+
+func TestWeatherHandler_Create(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	post := func(router *gin.Engine, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/weather", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("coordinates", func(t *testing.T) {
+		// Arrange
+		mockRepo := mocks.NewWeatherRepository(t)
+		provider := &stubProvider{}
+		router := gin.New()
+		router.POST("/weather", handler.NewWeatherHandler(service.NewWeatherService(mockRepo, nil, provider)).Create)
+
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		// Act
+		w := post(router, `{"lat": 52.52, "lon": 13.405, "units": "metric"}`)
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, &domain.Coordinates{Lat: 52.52, Lon: 13.405}, provider.coords)
+
+		var resp domain.Weather
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, &domain.Coordinates{Lat: 52.52, Lon: 13.405}, resp.Coord)
+		assert.Equal(t, 21.0, resp.Temperature)
+	})
+
+	t.Run("invalid-requests", func(t *testing.T) {
+		router := gin.New()
+		router.POST("/weather", handler.NewWeatherHandler(service.NewWeatherService(mocks.NewWeatherRepository(t), nil, &stubProvider{})).Create)
+
+		for name, body := range map[string]string{
+			"city-and-coordinates": `{"cityName": "berlin", "country": "de", "lat": 52.52, "lon": 13.405, "units": "metric"}`,
+			"lat-without-lon":      `{"lat": 52.52, "units": "metric"}`,
+			"lat-out-of-range":     `{"lat": 91, "lon": 13.405, "units": "metric"}`,
+			"lon-out-of-range":     `{"lat": 52.52, "lon": -180.5, "units": "metric"}`,
+			"city-without-country": `{"cityName": "berlin", "units": "metric"}`,
+			"neither":              `{"units": "metric"}`,
+		} {
+			assert.Equal(t, http.StatusBadRequest, post(router, body).Code, name)
+		}
+	})
+}
+
+func TestWeatherHandler_GetAll(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("filters-and-page", func(t *testing.T) {
+		// Arrange
+		mockRepo := mocks.NewWeatherRepository(t)
+		h := handler.NewWeatherHandler(service.NewWeatherService(mockRepo, nil, nil))
+
+		router := gin.New()
+		router.GET("/weather", h.GetAll)
+
+		from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		mockRepo.On("List", mock.Anything, mock.MatchedBy(func(q domain.WeatherQuery) bool {
+			return q.CityName == "london" &&
+				q.Country == "gb" &&
+				q.Unit == domain.Metric &&
+				q.FetchedFrom.Equal(from) &&
+				q.FetchedTo == nil &&
+				*q.MinTemperature == -5.5 &&
+				q.MaxTemperature == nil &&
+				q.Sort == domain.WeatherSort{Field: domain.SortByTemperature} &&
+				q.Limit == domain.DefaultPageSize &&
+				q.Cursor == "abc"
+		})).Return(&domain.WeatherPage{Items: []domain.Weather{{CityName: "london"}}, NextCursor: "def"}, nil)
+
+		// Act
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/weather?city=London&country=GB&unit=metric&from=2026-01-01T00:00:00Z&min_temp=-5.5&sort=temperature&cursor=abc", nil)
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp domain.WeatherPage
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Len(t, resp.Items, 1)
+		assert.Equal(t, "def", resp.NextCursor)
+	})
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "unknown-sort-field", query: "sort=-humidity"},
+		{name: "limit-too-large", query: "limit=1000"},
+		{name: "empty-time-range", query: "from=2026-01-02T00:00:00Z&to=2026-01-01T00:00:00Z"},
+		{name: "empty-temperature-range", query: "min_temp=30&max_temp=10"},
+		{name: "malformed-time", query: "from=yesterday"},
+		{name: "malformed-city-id", query: "city_id=42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler.NewWeatherHandler(service.NewWeatherService(mocks.NewWeatherRepository(t), nil, nil))
+
+			router := gin.New()
+			router.GET("/weather", h.GetAll)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/weather?"+tt.query, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestWeatherHandler_GetHistory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "malformed-interval", query: "interval=hourly"},
+		{name: "malformed-from", query: "from=2026-03-01"},
+		{name: "unknown-units", query: "units=kelvin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler.NewWeatherHandler(service.NewWeatherService(mocks.NewWeatherRepository(t), mocks.NewCityRepository(t), nil))
+
+			router := gin.New()
+			router.GET("/weather/history/:cityName", h.GetHistory)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/weather/history/london?"+tt.query, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestWeatherHandler_GetCurrent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("serves-fresh-record", func(t *testing.T) {
+		// Arrange
+		weatherRepo := mocks.NewWeatherRepository(t)
+		cityRepo := mocks.NewCityRepository(t)
+		h := handler.NewWeatherHandler(service.NewWeatherService(weatherRepo, cityRepo, nil))
+
+		router := gin.New()
+		router.GET("/weather/current/:cityName", h.GetCurrent)
+
+		london := domain.City{ID: uuid.New(), Name: "London", Country: "gb"}
+		cityRepo.On("FindByName", mock.Anything, "london", "gb").Return([]domain.City{london}, nil)
+		weatherRepo.On("List", mock.Anything, mock.MatchedBy(func(q domain.WeatherQuery) bool {
+			return q.Unit == domain.Metric && *q.CityID == london.ID
+		})).Return(&domain.WeatherPage{Items: []domain.Weather{{CityName: "london", Temperature: 9, FetchedAt: time.Now().Add(-30 * time.Minute)}}}, nil)
+
+		// Act
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/weather/current/london?country=GB&max_age=1h", nil)
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp domain.CurrentWeather
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, domain.SourceStorage, resp.Source)
+		assert.Equal(t, 9.0, resp.Temperature)
+	})
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "malformed-max-age", query: "max_age=soon"},
+		{name: "negative-max-age", query: "max_age=-5m"},
+		{name: "unknown-units", query: "units=kelvin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := handler.NewWeatherHandler(service.NewWeatherService(mocks.NewWeatherRepository(t), mocks.NewCityRepository(t), nil))
+
+			router := gin.New()
+			router.GET("/weather/current/:cityName", h.GetCurrent)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/weather/current/london?"+tt.query, nil)
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
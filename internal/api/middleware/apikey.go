@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/xoltawn/weatherhub/internal/domain"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyAuth authenticates requests carrying an X-API-Key header.
+func APIKeyAuth(keys domain.APIKeyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity, err := keys.Authenticate(c.Request.Context(), c.GetHeader(APIKeyHeader))
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		setIdentity(c, identity)
+		c.Next()
+	}
+}
+
+// Authenticate accepts either an X-API-Key header or a bearer token. The API key wins when both are sent.
+func Authenticate(cfg JWTConfig, keys domain.APIKeyService) gin.HandlerFunc {
+	jwtAuth := JWTAuth(cfg)
+	keyAuth := APIKeyAuth(keys)
+
+	return func(c *gin.Context) {
+		if c.GetHeader(APIKeyHeader) != "" {
+			keyAuth(c)
+			return
+		}
+
+		jwtAuth(c)
+	}
+}
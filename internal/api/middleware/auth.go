@@ -1 +1,128 @@
 package middleware
+
+import (
+	"crypto/rsa"
+	"errors"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/xoltawn/weatherhub/internal/api/handler"
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/pkg/errutil"
+)
+
+// JWTConfig holds the keys and expected claims used to verify bearer tokens.
+// HS256 tokens are accepted when Secret is set and RS256 tokens when PublicKey is set.
+type JWTConfig struct {
+	Secret    []byte
+	PublicKey *rsa.PublicKey
+	Issuer    string
+	Audience  string
+}
+
+// Claims are the token claims understood by JWTAuth. Scope holds space-delimited scopes as in RFC 8693.
+type Claims struct {
+	Scope string `json:"scope,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// JWTAuth verifies the bearer token of every request and stores the caller's identity in the request context.
+func JWTAuth(cfg JWTConfig) gin.HandlerFunc {
+	parser := newParser(cfg)
+
+	return func(c *gin.Context) {
+		token, err := bearerToken(c.GetHeader("Authorization"))
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		claims, err := parseClaims(parser, cfg, token)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		setIdentity(c, &domain.Identity{
+			Subject: claims.Subject,
+			Scopes:  strings.Fields(claims.Scope),
+		})
+		c.Next()
+	}
+}
+
+func setIdentity(c *gin.Context, identity *domain.Identity) {
+	c.Request = c.Request.WithContext(domain.ContextWithIdentity(c.Request.Context(), identity))
+}
+
+func newParser(cfg JWTConfig) *jwt.Parser {
+	var methods []string
+	if len(cfg.Secret) > 0 {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if cfg.PublicKey != nil {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+
+	return jwt.NewParser(opts...)
+}
+
+func parseClaims(parser *jwt.Parser, cfg JWTConfig, tokenStr string) (*Claims, error) {
+	claims := &Claims{}
+
+	_, err := parser.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (any, error) {
+		switch token.Method.(type) {
+		case *jwt.SigningMethodHMAC:
+			if len(cfg.Secret) == 0 {
+				return nil, jwt.ErrTokenUnverifiable
+			}
+			return cfg.Secret, nil
+		case *jwt.SigningMethodRSA:
+			if cfg.PublicKey == nil {
+				return nil, jwt.ErrTokenUnverifiable
+			}
+			return cfg.PublicKey, nil
+		default:
+			return nil, jwt.ErrTokenUnverifiable
+		}
+	})
+	if err != nil {
+		return nil, errutil.Wrap(domain.ErrUnauthorized, err.Error())
+	}
+
+	if claims.Subject == "" {
+		return nil, errutil.Wrap(domain.ErrUnauthorized, "token has no subject")
+	}
+
+	return claims, nil
+}
+
+func bearerToken(header string) (string, error) {
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", errutil.Wrap(domain.ErrUnauthorized, "missing bearer token")
+	}
+
+	return strings.TrimSpace(token), nil
+}
+
+func abortWithError(c *gin.Context, err error) {
+	if errors.Is(err, domain.ErrUnauthorized) {
+		c.Header("WWW-Authenticate", `Bearer realm="weatherhub"`)
+	}
+
+	handler.RespondWithError(c, err)
+	c.Abort()
+}
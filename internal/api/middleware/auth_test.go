@@ -0,0 +1,115 @@
+package middleware_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xoltawn/weatherhub/internal/api/middleware"
+	"github.com/xoltawn/weatherhub/internal/domain"
+)
+
+var testSecret = []byte("test-secret")
+
+func signHS256(t *testing.T, claims jwt.Claims) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(testSecret)
+	require.NoError(t, err)
+
+	return token
+}
+
+func validClaims() middleware.Claims {
+	return middleware.Claims{
+		Scope: "weather:read weather:write",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "user-1",
+			Issuer:    "weatherhub-test",
+			Audience:  jwt.ClaimStrings{"weatherhub"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+}
+
+func TestJWTAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	cfg := middleware.JWTConfig{
+		Secret:    testSecret,
+		PublicKey: &rsaKey.PublicKey,
+		Issuer:    "weatherhub-test",
+		Audience:  "weatherhub",
+	}
+
+	router := gin.New()
+	router.GET("/protected", middleware.JWTAuth(cfg), func(c *gin.Context) {
+		identity, ok := domain.IdentityFromContext(c.Request.Context())
+		if !ok {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.String(http.StatusOK, identity.Subject+" "+strings.Join(identity.Scopes, ","))
+	})
+
+	expired := validClaims()
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+
+	wrongIssuer := validClaims()
+	wrongIssuer.Issuer = "someone-else"
+
+	wrongAudience := validClaims()
+	wrongAudience.Audience = jwt.ClaimStrings{"other-service"}
+
+	noExpiry := validClaims()
+	noExpiry.ExpiresAt = nil
+
+	rs256, err := jwt.NewWithClaims(jwt.SigningMethodRS256, validClaims()).SignedString(rsaKey)
+	require.NoError(t, err)
+
+	badSignature, err := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims()).SignedString([]byte("wrong"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		header     string
+		wantStatus int
+	}{
+		{name: "hs256", header: "Bearer " + signHS256(t, validClaims()), wantStatus: http.StatusOK},
+		{name: "rs256", header: "Bearer " + rs256, wantStatus: http.StatusOK},
+		{name: "missing-header", header: "", wantStatus: http.StatusUnauthorized},
+		{name: "wrong-scheme", header: "Basic abc", wantStatus: http.StatusUnauthorized},
+		{name: "bad-signature", header: "Bearer " + badSignature, wantStatus: http.StatusUnauthorized},
+		{name: "expired", header: "Bearer " + signHS256(t, expired), wantStatus: http.StatusUnauthorized},
+		{name: "no-expiry", header: "Bearer " + signHS256(t, noExpiry), wantStatus: http.StatusUnauthorized},
+		{name: "wrong-issuer", header: "Bearer " + signHS256(t, wrongIssuer), wantStatus: http.StatusUnauthorized},
+		{name: "wrong-audience", header: "Bearer " + signHS256(t, wrongAudience), wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/protected", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, "user-1 weather:read,weather:write", w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"context"
+	"slices"
+)
+
+const (
+	ScopeWeatherRead  = "weather:read"
+	ScopeWeatherWrite = "weather:write"
+	ScopeWeatherAdmin = "weather:admin"
+)
+
+// KnownScopes lists every scope that can be granted to a caller.
+var KnownScopes = []string{ScopeWeatherRead, ScopeWeatherWrite, ScopeWeatherAdmin}
+
+// Identity is the authenticated caller of a request.
+type Identity struct {
+	Subject string
+	Scopes  []string
+}
+
+// HasAnyScope reports whether the identity was granted at least one of the given scopes.
+func (i *Identity) HasAnyScope(scopes ...string) bool {
+	for _, scope := range scopes {
+		if slices.Contains(i.Scopes, scope) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type identityCtxKey struct{}
+
+func ContextWithIdentity(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, identityCtxKey{}, identity)
+}
+
+func IdentityFromContext(ctx context.Context) (*Identity, bool) {
+	identity, ok := ctx.Value(identityCtxKey{}).(*Identity)
+	return identity, ok && identity != nil
+}
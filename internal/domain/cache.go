@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CacheOp is a cache write that invalidates what's cached under a key.
+type CacheOp string
+
+const (
+	// CacheOpDelete deletes the key.
+	CacheOpDelete CacheOp = "del"
+	// CacheOpIncr bumps the generation under the key.
+	CacheOpIncr CacheOp = "incr"
+)
+
+// CacheInvalidation is a cache invalidation that failed, kept until a retry succeeds so that the
+// cache converges with the database. There's at most one per operation and key.
+type CacheInvalidation struct {
+	ID            uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	Op            CacheOp   `json:"op" gorm:"uniqueIndex:idx_cache_invalidations_op_key"`
+	Key           string    `json:"key" gorm:"uniqueIndex:idx_cache_invalidations_op_key"`
+	Attempts      int       `json:"attempts"`
+	NextAttemptAt time.Time `json:"next_attempt_at" gorm:"index"`
+	LastError     string    `json:"last_error,omitempty"`
+	// Version is bumped whenever the invalidation is recorded again while it's pending, so that
+	// a replay only removes the invalidation it applied.
+	Version   int       `json:"version" gorm:"not null;default:1"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+//go:generate mockery --name=CacheInvalidationRepository --output=../repository/mocks --case=underscore
+type CacheInvalidationRepository interface {
+	// Create records an invalidation. One with the same operation and key that's pending is made
+	// due again, with its version bumped, instead.
+	Create(ctx context.Context, inv *CacheInvalidation) error
+	// Delete removes an invalidation, unless it has been recorded again since version was read.
+	Delete(ctx context.Context, id uuid.UUID, version int) error
+	// GetDue returns up to limit invalidations whose next attempt is at or before now, most overdue first.
+	GetDue(ctx context.Context, now time.Time, limit int) ([]CacheInvalidation, error)
+	// RecordAttempt stores a failed attempt and when to try next.
+	RecordAttempt(ctx context.Context, id uuid.UUID, nextAttemptAt time.Time, attemptErr string) error
+}
+
+// CacheInvalidator applies invalidations to the cache.
+type CacheInvalidator interface {
+	Invalidate(ctx context.Context, inv *CacheInvalidation) error
+}
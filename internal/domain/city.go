@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// City is a canonical place that weather records refer to. A name alone doesn't identify a city:
+// London GB and London CA are different rows.
+type City struct {
+	ID uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	// ProviderID identifies the place at the provider it was first resolved by, e.g. "openweathermap:2643743".
+	ProviderID     *string      `json:"provider_id,omitempty" gorm:"uniqueIndex"`
+	Name           string       `json:"name"`
+	NormalizedName string       `json:"-" gorm:"index:idx_city_lookup"`
+	Country        string       `json:"country" gorm:"index:idx_city_lookup"`
+	Coord          *Coordinates `json:"coord,omitempty" gorm:"serializer:json"`
+	// Timezone is an IANA time zone name, when the provider reports one.
+	Timezone string `json:"timezone,omitempty"`
+	// Aliases are other normalized names the city was requested by, e.g. "londres".
+	Aliases   []string  `json:"aliases,omitempty" gorm:"type:jsonb;serializer:json"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Clone returns a copy of c that shares no pointers or slices with it.
+func (c *City) Clone() *City {
+	if c == nil {
+		return nil
+	}
+
+	clone := *c
+	if c.ProviderID != nil {
+		providerID := *c.ProviderID
+		clone.ProviderID = &providerID
+	}
+	clone.Coord = c.Coord.clone()
+	clone.Aliases = slices.Clone(c.Aliases)
+
+	return &clone
+}
+
+// NormalizeCityName returns the form city names are matched by.
+func NormalizeCityName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+//go:generate mockery --name=CityRepository --output=../repository/mocks --case=underscore
+type CityRepository interface {
+	// Create fails with ErrAlreadyExists when a city with the same provider ID, or the same
+	// normalized name in the same country, exists.
+	Create(ctx context.Context, city *City) error
+	Update(ctx context.Context, city *City) error
+	GetByID(ctx context.Context, id uuid.UUID) (*City, error)
+	GetByProviderID(ctx context.Context, providerID string) (*City, error)
+	// FindByName returns the cities whose normalized name or one of whose aliases matches name.
+	// An empty country matches every country.
+	FindByName(ctx context.Context, name, country string) ([]City, error)
+}
+
+type CityService interface {
+	Find(ctx context.Context, name, country string) ([]City, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*City, error)
+	// Search looks query up in the offline gazetteer, for autocompletion.
+	Search(ctx context.Context, query, country string, limit int) ([]Place, error)
+}
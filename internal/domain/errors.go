@@ -1,6 +1,10 @@
 package domain
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 var (
 	ErrNotFound      = errors.New("resource not found")
@@ -8,4 +12,33 @@ var (
 	ErrInvalidInput  = errors.New("invalid input data")
 	ErrThirdParty    = errors.New("external service error")
 	ErrAlreadyExists = errors.New("record already exists")
+	ErrUnauthorized  = errors.New("authentication required")
+	ErrForbidden     = errors.New("insufficient permissions")
+	ErrAmbiguous     = errors.New("request matches more than one resource")
 )
+
+// UpstreamError describes a failed call to a third-party service. It matches ErrThirdParty
+// as well as the underlying transport error, if any.
+type UpstreamError struct {
+	// StatusCode is the HTTP status returned by the upstream, or 0 when no response was received.
+	StatusCode int
+	// RetryAfter is the delay requested by the upstream through a Retry-After header.
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *UpstreamError) Error() string {
+	if e.StatusCode == 0 {
+		return fmt.Sprintf("upstream request failed: %v", e.Err)
+	}
+
+	return fmt.Sprintf("upstream returned status %d", e.StatusCode)
+}
+
+func (e *UpstreamError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{ErrThirdParty}
+	}
+
+	return []error{ErrThirdParty, e.Err}
+}
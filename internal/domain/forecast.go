@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Forecast is a single forecast run for a city: what the provider predicted at IssuedAt for
+// each of the following time steps.
+type Forecast struct {
+	ID       uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	CityName string    `json:"city_name" gorm:"index:idx_forecast_city_country"`
+	Country  string    `json:"country" gorm:"index:idx_forecast_city_country"`
+	Unit     Unit      `json:"unit"`
+	Provider string    `json:"provider"`
+	// IssuedAt is when the provider produced the forecast. Providers that don't report it use the fetch time.
+	IssuedAt  time.Time      `json:"issued_at"`
+	FetchedAt time.Time      `json:"fetched_at" gorm:"index"`
+	Steps     []ForecastStep `json:"steps" gorm:"constraint:OnDelete:CASCADE"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// ForecastStep is the predicted weather at ValidAt.
+type ForecastStep struct {
+	ForecastID  uuid.UUID `json:"-" gorm:"type:uuid;primaryKey"`
+	ValidAt     time.Time `json:"valid_at" gorm:"primaryKey"`
+	Temperature float64   `json:"temperature"`
+	Description string    `json:"description"`
+	Humidity    int       `json:"humidity"`
+	WindSpeed   float64   `json:"wind_speed"`
+	// PrecipitationProbability ranges from 0 to 1.
+	PrecipitationProbability float64 `json:"precipitation_probability"`
+}
+
+//go:generate mockery --name=ForecastRepository --output=../repository/mocks --case=underscore
+type ForecastRepository interface {
+	Create(ctx context.Context, forecast *Forecast) error
+	// GetLatest returns the most recently fetched run for the city, with its steps in time order.
+	GetLatest(ctx context.Context, cityName, country string, units Unit) (*Forecast, error)
+}
+
+type ForecastService interface {
+	// GetForecast returns the steps of the latest forecast run valid within the next hours,
+	// fetching a new run when the stored one is missing or stale.
+	GetForecast(ctx context.Context, cityName, country string, units Unit, hours int) (*Forecast, error)
+}
+
+type ForecastData struct {
+	IssuedAt    time.Time
+	CityName    string
+	CountryCode string
+	Provider    string
+	Steps       []ForecastStep
+}
+
+// ForecastProvider fetches multi-step forecasts, as opposed to WeatherProvider's current conditions.
+type ForecastProvider interface {
+	GetHourlyForecast(ctx context.Context, city, country string, units Unit) (*ForecastData, error)
+}
@@ -0,0 +1,10 @@
+package domain
+
+// Leader tells whether this replica is the one to run background work that must not run on every
+// replica, such as polling the watchlist.
+type Leader interface {
+	IsLeader() bool
+	// Token returns the fencing token of the leadership held, if any. Tokens only grow, so the
+	// writes the leader makes can carry it for stores to refuse those of a deposed leader.
+	Token() (int64, bool)
+}
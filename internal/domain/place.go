@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"context"
+)
+
+// Place is a populated place from an offline gazetteer such as GeoNames. Unlike City, places are
+// imported in bulk rather than learned from provider responses, so they can be searched without
+// spending provider quota.
+type Place struct {
+	// ID is the gazetteer's own identifier, e.g. the GeoNames ID.
+	ID        int64  `json:"id" gorm:"primaryKey;autoIncrement:false"`
+	Name      string `json:"name"`
+	ASCIIName string `json:"ascii_name"`
+	Country   string `json:"country" gorm:"index"`
+	// Admin1 and Admin2 are the first- and second-level administrative regions. Admin1 is a name
+	// such as "England" when the import had region names, and a code otherwise.
+	Admin1     string      `json:"admin1,omitempty"`
+	Admin2     string      `json:"admin2,omitempty"`
+	Coord      Coordinates `json:"coord" gorm:"embedded"`
+	Population int64       `json:"population"`
+	Timezone   string      `json:"timezone,omitempty"`
+	// Names are the normalized names and alternate names the place is searched by.
+	Names []PlaceName `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+type PlaceName struct {
+	PlaceID int64  `gorm:"primaryKey;autoIncrement:false"`
+	Name    string `gorm:"primaryKey"`
+}
+
+//go:generate mockery --name=PlaceRepository --output=../repository/mocks --case=underscore
+type PlaceRepository interface {
+	// Upsert inserts places, replacing the stored place and names of any that already exist.
+	Upsert(ctx context.Context, places []Place) error
+	// Search returns up to limit places with a name that starts with or resembles query, prefix
+	// matches first and the most populous first within each group. An empty country matches
+	// every country.
+	Search(ctx context.Context, query, country string, limit int) ([]Place, error)
+	// Exists reports whether a place in country, or in any country if country is empty, is known by name.
+	Exists(ctx context.Context, name, country string) (bool, error)
+}
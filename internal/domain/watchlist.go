@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MinWatchInterval is the shortest interval a location can be polled at.
+const MinWatchInterval = time.Minute
+
+// WatchlistEntry is a location whose weather is fetched and stored on a schedule: every Interval,
+// a Go duration such as "15m", or at the times of Cron, a five-field cron expression evaluated
+// in UTC. Exactly one of the two is set.
+type WatchlistEntry struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	CityName  string    `json:"city_name"`
+	Country   string    `json:"country"`
+	Unit      Unit      `json:"unit"`
+	Interval  string    `json:"interval,omitempty"`
+	Cron      string    `json:"cron,omitempty"`
+	Enabled   bool      `json:"enabled"`
+	NextRunAt time.Time `json:"next_run_at" gorm:"index"`
+	// LastRunAt and LastError describe the latest completed run; LastError is empty when it succeeded.
+	LastRunAt *time.Time `json:"last_run_at,omitempty"`
+	LastError string     `json:"last_error,omitempty"`
+	// FenceToken is the fencing token of the latest leader to claim a run of the entry.
+	FenceToken int64     `json:"-" gorm:"not null;default:0"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+//go:generate mockery --name=WatchlistRepository --output=../repository/mocks --case=underscore
+type WatchlistRepository interface {
+	Create(ctx context.Context, entry *WatchlistEntry) error
+	Update(ctx context.Context, entry *WatchlistEntry) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	GetByID(ctx context.Context, id uuid.UUID) (*WatchlistEntry, error)
+	GetAll(ctx context.Context) ([]WatchlistEntry, error)
+	// GetDue returns up to limit enabled entries whose next run is at or before now, most overdue first.
+	GetDue(ctx context.Context, now time.Time, limit int) ([]WatchlistEntry, error)
+	// Claim moves an entry's next run from dueAt, as read by GetDue, to nextRunAt, leaving its
+	// other fields alone. It reports false when the next run was moved since, i.e. when another
+	// scheduler claimed the run first, or when a leader with a newer fencing token claimed the
+	// entry. A zero token, from a scheduler that runs without leader election, isn't fenced.
+	Claim(ctx context.Context, id uuid.UUID, dueAt, nextRunAt time.Time, token int64) (bool, error)
+	// RecordRun stores the outcome of a run, leaving the entry's other fields alone. Outcomes
+	// carrying a fencing token older than the entry's are dropped.
+	RecordRun(ctx context.Context, id uuid.UUID, ranAt time.Time, runErr string, token int64) error
+}
+
+type WatchlistService interface {
+	Create(ctx context.Context, entry *WatchlistEntry) (*WatchlistEntry, error)
+	Update(ctx context.Context, id uuid.UUID, updates *WatchlistEntry) (*WatchlistEntry, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	GetByID(ctx context.Context, id uuid.UUID) (*WatchlistEntry, error)
+	GetAll(ctx context.Context) ([]WatchlistEntry, error)
+}
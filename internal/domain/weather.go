@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,36 +16,97 @@ const (
 )
 
 type Weather struct {
-	ID          uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
-	CityName    string    `json:"city_name" gorm:"index;index:idx_city_country"`
-	Country     string    `json:"country" gorm:"index:idx_city_country"`
-	Temperature float64   `json:"temperature"`
-	Unit        Unit      `json:"unit"`
-	Description string    `json:"description"`
-	Humidity    int       `json:"humidity"`
-	WindSpeed   float64   `json:"wind_speed"`
-	FetchedAt   time.Time `json:"fetched_at"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;index:idx_weathers_fetched_at_id,priority:2"`
+	// CityID references the resolved city. It's nil for locations no city could be resolved for.
+	CityID *uuid.UUID `json:"city_id,omitempty" gorm:"type:uuid;index;index:idx_weathers_city_fetched_at,priority:1"`
+	City   *City      `json:"city,omitempty" gorm:"constraint:OnDelete:SET NULL"`
+	// CityName and Country hold the name as requested. Lookups go through CityID.
+	CityName    string  `json:"city_name" gorm:"index;index:idx_city_country"`
+	Country     string  `json:"country" gorm:"index:idx_city_country"`
+	Temperature float64 `json:"temperature"`
+	Unit        Unit    `json:"unit"`
+	Description string  `json:"description"`
+	Humidity    int     `json:"humidity"`
+	WindSpeed   float64 `json:"wind_speed"`
+	Provider    string  `json:"provider"`
+	// Coord is the location the weather was reported for, when known.
+	Coord *Coordinates `json:"coord,omitempty" gorm:"serializer:json"`
+	// FetchedAt is indexed with ID for the default listing order, and with CityID for latest
+	// records and history windows.
+	FetchedAt time.Time `json:"fetched_at" gorm:"index:idx_weathers_fetched_at_id,priority:1;index:idx_weathers_city_fetched_at,priority:2"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Clone returns a copy of w that shares no pointers with it, so that the copy can be handed to
+// another caller.
+func (w *Weather) Clone() *Weather {
+	if w == nil {
+		return nil
+	}
+
+	clone := *w
+	if w.CityID != nil {
+		cityID := *w.CityID
+		clone.CityID = &cityID
+	}
+	clone.City = w.City.Clone()
+	clone.Coord = w.Coord.clone()
+
+	return &clone
+}
+
+// DefaultMaxAge is how old a stored record can be and still count as the current weather.
+const DefaultMaxAge = 15 * time.Minute
+
+// WeatherSource tells where the current weather was served from.
+type WeatherSource string
+
+const (
+	// SourceStorage marks a stored record that was fresh enough to serve.
+	SourceStorage WeatherSource = "storage"
+	// SourceProvider marks a record fetched from the providers for the request.
+	SourceProvider WeatherSource = "provider"
+)
+
+// CurrentWeather is a weather record along with where it was served from.
+type CurrentWeather struct {
+	Weather
+	Source WeatherSource `json:"source"`
 }
 
 //go:generate mockery --name=WeatherRepository --output=../repository/mocks --case=underscore
 type WeatherRepository interface {
 	Create(ctx context.Context, weather *Weather) error
-	GetAll(ctx context.Context) ([]Weather, error)
+	List(ctx context.Context, query WeatherQuery) (*WeatherPage, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*Weather, error)
-	GetLatestByCity(ctx context.Context, cityName string) (*Weather, error)
+	GetLatestByCity(ctx context.Context, cityID uuid.UUID) (*Weather, error)
+	// GetHistory returns up to limit observations of the query's window, oldest first.
+	GetHistory(ctx context.Context, query WeatherHistoryQuery, limit int) ([]Weather, error)
+	// GetHistoryBuckets aggregates the observations of the query's window per interval, oldest first.
+	GetHistoryBuckets(ctx context.Context, query WeatherHistoryQuery) ([]WeatherBucket, error)
 	Update(ctx context.Context, weather *Weather) error
 	Delete(ctx context.Context, id uuid.UUID) error
 }
 
 type WeatherService interface {
 	FetchAndStore(ctx context.Context, cityName, country string, units Unit) (*Weather, error)
-	GetAllRecords(ctx context.Context) ([]Weather, error)
+	FetchAndStoreByCoordinates(ctx context.Context, coords Coordinates, units Unit) (*Weather, error)
+	ListRecords(ctx context.Context, query WeatherQuery) (*WeatherPage, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*Weather, error)
 	UpdateRecord(ctx context.Context, id uuid.UUID, updates *Weather) (*Weather, error)
 	DeleteRecord(ctx context.Context, id uuid.UUID) error
-	GetLatest(ctx context.Context, cityName string) (*Weather, error)
+	// GetLatest returns the latest record for the city with that name, in the given country if
+	// not empty. It fails with ErrAmbiguous when several cities match.
+	GetLatest(ctx context.Context, cityName, country string) (*Weather, error)
+	GetLatestByCityID(ctx context.Context, cityID uuid.UUID) (*Weather, error)
+	// GetCurrent returns the latest record of the city in units if it was fetched within maxAge,
+	// and otherwise fetches, stores and returns a new one. A zero maxAge always fetches. Like
+	// GetLatest it fails with ErrAmbiguous when several stored cities match.
+	GetCurrent(ctx context.Context, cityName, country string, units Unit, maxAge time.Duration) (*CurrentWeather, error)
+	// GetHistory returns the weather of the city with that name over a window, resolving the
+	// name like GetLatest; the query's CityID is ignored.
+	GetHistory(ctx context.Context, cityName, country string, query WeatherHistoryQuery) (*WeatherHistory, error)
 }
 
 type WeatherData struct {
@@ -54,8 +116,44 @@ type WeatherData struct {
 	Description string
 	CityName    string
 	CountryCode string
+	Provider    string
+	Coord       *Coordinates
+	// ProviderCityID is the provider's own ID for the place, if it has one.
+	ProviderCityID string
+	Timezone       string
 }
 
 type WeatherProvider interface {
 	GetForecast(ctx context.Context, city, country string, units Unit) (*WeatherData, error)
+	// GetForecastByCoordinates reports the weather at a location. CityName and CountryCode are
+	// left empty when the provider can't name the place.
+	GetForecastByCoordinates(ctx context.Context, coords Coordinates, units Unit) (*WeatherData, error)
+}
+
+type Coordinates struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+func (c *Coordinates) clone() *Coordinates {
+	if c == nil {
+		return nil
+	}
+
+	clone := *c
+	return &clone
+}
+
+// Validate checks that the coordinates are a WGS 84 latitude and longitude.
+func (c Coordinates) Validate() error {
+	if c.Lat < -90 || c.Lat > 90 || c.Lon < -180 || c.Lon > 180 {
+		return fmt.Errorf("coordinates %g,%g are out of range: %w", c.Lat, c.Lon, ErrInvalidInput)
+	}
+
+	return nil
+}
+
+// Geocoder resolves a city and ISO 3166 country code to coordinates.
+type Geocoder interface {
+	Geocode(ctx context.Context, city, country string) (*Coordinates, error)
 }
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultHistoryWindow is the window a history covers when no range is given.
+	DefaultHistoryWindow = 24 * time.Hour
+	// MinHistoryInterval is the smallest bucket width.
+	MinHistoryInterval = time.Minute
+	// MaxHistoryPoints bounds both the observations and the buckets a history returns.
+	MaxHistoryPoints = 1000
+)
+
+// HistoryBucketOrigin is the time buckets are aligned to.
+var HistoryBucketOrigin = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// WeatherHistoryQuery selects the weather of one city over [From, To), in one unit so that
+// temperatures can be aggregated. A zero Interval asks for the raw observations.
+type WeatherHistoryQuery struct {
+	CityID   uuid.UUID
+	Unit     Unit
+	From     time.Time
+	To       time.Time
+	Interval time.Duration
+}
+
+// Validate checks the query and fills in the default window, ending now, and unit.
+func (q *WeatherHistoryQuery) Validate() error {
+	if q.To.IsZero() {
+		q.To = time.Now()
+	}
+	if q.From.IsZero() {
+		q.From = q.To.Add(-DefaultHistoryWindow)
+	}
+	if q.Unit == "" {
+		q.Unit = Metric
+	}
+
+	if !q.From.Before(q.To) {
+		return fmt.Errorf("history window is empty: %w", ErrInvalidInput)
+	}
+
+	if q.Interval != 0 {
+		if q.Interval < MinHistoryInterval {
+			return fmt.Errorf("interval must be at least %s: %w", MinHistoryInterval, ErrInvalidInput)
+		}
+		if q.bucketCount() > MaxHistoryPoints {
+			return fmt.Errorf("window spans more than %d intervals of %s: %w", MaxHistoryPoints, q.Interval, ErrInvalidInput)
+		}
+	}
+
+	return nil
+}
+
+// bucketCount returns how many aligned buckets the window overlaps, counting the partial ones
+// at either end.
+func (q *WeatherHistoryQuery) bucketCount() int64 {
+	first := floorDiv(q.From.Sub(HistoryBucketOrigin), q.Interval)
+	last := floorDiv(q.To.Sub(HistoryBucketOrigin)-1, q.Interval)
+
+	return last - first + 1
+}
+
+// floorDiv divides d by interval, rounding down rather than towards zero.
+func floorDiv(d, interval time.Duration) int64 {
+	n := d / interval
+	if d%interval < 0 {
+		n--
+	}
+
+	return int64(n)
+}
+
+// WeatherBucket aggregates the observations fetched in [Start, Start+interval). Buckets are
+// aligned to multiples of the interval since HistoryBucketOrigin, so the first and last may extend
+// past the window; only observations within it are counted.
+type WeatherBucket struct {
+	Start          time.Time `json:"start"`
+	Count          int       `json:"count"`
+	AvgTemperature float64   `json:"avg_temperature"`
+	MinTemperature float64   `json:"min_temperature"`
+	MaxTemperature float64   `json:"max_temperature"`
+	AvgHumidity    float64   `json:"avg_humidity"`
+	MaxWindSpeed   float64   `json:"max_wind_speed"`
+}
+
+// WeatherHistory holds either the observations of a window, oldest first, or their per-interval
+// buckets. Intervals without observations have no bucket.
+type WeatherHistory struct {
+	City         *City           `json:"city"`
+	Unit         Unit            `json:"unit"`
+	From         time.Time       `json:"from"`
+	To           time.Time       `json:"to"`
+	Interval     string          `json:"interval,omitempty"`
+	Observations []Weather       `json:"observations,omitempty"`
+	Buckets      []WeatherBucket `json:"buckets,omitempty"`
+}
@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// WeatherSortField is a field weather records can be listed in the order of.
+type WeatherSortField string
+
+const (
+	SortByFetchedAt   WeatherSortField = "fetched_at"
+	SortByTemperature WeatherSortField = "temperature"
+	SortByCityName    WeatherSortField = "city_name"
+)
+
+func (f WeatherSortField) Valid() bool {
+	switch f {
+	case SortByFetchedAt, SortByTemperature, SortByCityName:
+		return true
+	}
+	return false
+}
+
+type WeatherSort struct {
+	Field WeatherSortField
+	Desc  bool
+}
+
+// ParseWeatherSort parses a sort field, optionally prefixed by "-" for descending order, e.g.
+// "-fetched_at". An empty string gives the default order, newest first.
+func ParseWeatherSort(s string) (WeatherSort, error) {
+	if s == "" {
+		return WeatherSort{Field: SortByFetchedAt, Desc: true}, nil
+	}
+
+	sort := WeatherSort{Field: WeatherSortField(strings.TrimPrefix(s, "-")), Desc: strings.HasPrefix(s, "-")}
+	if !sort.Field.Valid() {
+		return WeatherSort{}, fmt.Errorf("cannot sort by %q: %w", sort.Field, ErrInvalidInput)
+	}
+
+	return sort, nil
+}
+
+func (s WeatherSort) String() string {
+	if s.Desc {
+		return "-" + string(s.Field)
+	}
+	return string(s.Field)
+}
+
+// WeatherQuery selects a page of weather records. Zero-valued filters match every record.
+type WeatherQuery struct {
+	CityID *uuid.UUID
+	// CityName matches the name records were requested by.
+	CityName string
+	Country  string
+	Unit     Unit
+	// FetchedFrom is inclusive and FetchedTo exclusive.
+	FetchedFrom *time.Time
+	FetchedTo   *time.Time
+	// MinTemperature and MaxTemperature are compared in each record's own unit; filter on Unit too
+	// to compare like with like.
+	MinTemperature *float64
+	MaxTemperature *float64
+	Sort           WeatherSort
+	Limit          int
+	// Cursor is the NextCursor of the previous page, empty for the first page. It's only valid
+	// with the sort order it was issued for.
+	Cursor string
+}
+
+// Validate checks the query and fills in the default sort order and page size.
+func (q *WeatherQuery) Validate() error {
+	if q.Sort.Field == "" {
+		q.Sort = WeatherSort{Field: SortByFetchedAt, Desc: true}
+	}
+	if !q.Sort.Field.Valid() {
+		return fmt.Errorf("cannot sort by %q: %w", q.Sort.Field, ErrInvalidInput)
+	}
+
+	switch {
+	case q.Limit == 0:
+		q.Limit = DefaultPageSize
+	case q.Limit < 0 || q.Limit > MaxPageSize:
+		return fmt.Errorf("limit must be between 1 and %d: %w", MaxPageSize, ErrInvalidInput)
+	}
+
+	if q.FetchedFrom != nil && q.FetchedTo != nil && !q.FetchedFrom.Before(*q.FetchedTo) {
+		return fmt.Errorf("fetched_at range is empty: %w", ErrInvalidInput)
+	}
+	if q.MinTemperature != nil && q.MaxTemperature != nil && *q.MinTemperature > *q.MaxTemperature {
+		return fmt.Errorf("temperature range is empty: %w", ErrInvalidInput)
+	}
+
+	return nil
+}
+
+type WeatherPage struct {
+	Items []Weather `json:"items"`
+	// NextCursor fetches the following page; it's empty on the last one.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Clone returns a copy of p whose items share nothing with those of p.
+func (p *WeatherPage) Clone() *WeatherPage {
+	if p == nil {
+		return nil
+	}
+
+	clone := *p
+	clone.Items = slices.Clone(p.Items)
+	for i := range clone.Items {
+		clone.Items[i] = *p.Items[i].Clone()
+	}
+
+	return &clone
+}
@@ -0,0 +1,91 @@
+package apikey
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/internal/repository"
+	"gorm.io/gorm"
+)
+
+type apiKeyRepo struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) domain.APIKeyRepository {
+	return &apiKeyRepo{db: db}
+}
+
+func (r *apiKeyRepo) Create(ctx context.Context, key *domain.APIKey) error {
+	err := r.db.
+		WithContext(ctx).
+		Create(key).Error
+	if err != nil {
+		return repository.MapGormError(err, "repository.APIKey.Create")
+	}
+
+	return nil
+}
+
+func (r *apiKeyRepo) GetAll(ctx context.Context) ([]domain.APIKey, error) {
+	var keys []domain.APIKey
+
+	err := r.db.
+		WithContext(ctx).
+		Order("created_at DESC").
+		Find(&keys).Error
+	if err != nil {
+		return nil, repository.MapGormError(err, "repository.APIKey.GetAll")
+	}
+
+	return keys, nil
+}
+
+func (r *apiKeyRepo) GetByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	var key domain.APIKey
+
+	err := r.db.
+		WithContext(ctx).
+		Take(&key, "key_hash = ?", keyHash).
+		Error
+	if err != nil {
+		return nil, repository.MapGormError(err, "repository.APIKey.GetByHash")
+	}
+
+	return &key, nil
+}
+
+func (r *apiKeyRepo) Revoke(ctx context.Context, id uuid.UUID, revokedAt time.Time) error {
+	res := r.db.
+		WithContext(ctx).
+		Model(&domain.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", revokedAt)
+	if res.Error != nil {
+		return repository.MapGormError(res.Error, "repository.APIKey.Revoke")
+	}
+
+	if res.RowsAffected == 0 {
+		return repository.MapGormError(gorm.ErrRecordNotFound, "repository.APIKey.Revoke")
+	}
+
+	return nil
+}
+
+// TouchLastUsed records usage of a key. Writes are skipped when the stored timestamp is less than a
+// minute old so that busy clients don't turn every authenticated request into an UPDATE.
+func (r *apiKeyRepo) TouchLastUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	err := r.db.
+		WithContext(ctx).
+		Model(&domain.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, usedAt.Add(-time.Minute)).
+		UpdateColumn("last_used_at", usedAt).
+		Error
+	if err != nil {
+		return repository.MapGormError(err, "repository.APIKey.TouchLastUsed")
+	}
+
+	return nil
+}
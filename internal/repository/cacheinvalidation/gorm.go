@@ -0,0 +1,83 @@
+package cacheinvalidation
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/internal/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type cacheInvalidationRepo struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) domain.CacheInvalidationRepository {
+	return &cacheInvalidationRepo{db: db}
+}
+
+func (r *cacheInvalidationRepo) Create(ctx context.Context, inv *domain.CacheInvalidation) error {
+	err := r.db.
+		WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "op"}, {Name: "key"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"version":         gorm.Expr("cache_invalidations.version + 1"),
+				"next_attempt_at": gorm.Expr("excluded.next_attempt_at"),
+				"last_error":      gorm.Expr("excluded.last_error"),
+			}),
+		}).
+		Create(inv).Error
+	if err != nil {
+		return repository.MapGormError(err, "repository.CacheInvalidation.Create")
+	}
+
+	return nil
+}
+
+func (r *cacheInvalidationRepo) Delete(ctx context.Context, id uuid.UUID, version int) error {
+	err := r.db.
+		WithContext(ctx).
+		Delete(&domain.CacheInvalidation{}, "id = ? AND version = ?", id, version).Error
+	if err != nil {
+		return repository.MapGormError(err, "repository.CacheInvalidation.Delete")
+	}
+
+	return nil
+}
+
+func (r *cacheInvalidationRepo) GetDue(ctx context.Context, now time.Time, limit int) ([]domain.CacheInvalidation, error) {
+	var invalidations []domain.CacheInvalidation
+
+	err := r.db.
+		WithContext(ctx).
+		Where("next_attempt_at <= ?", now).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&invalidations).Error
+	if err != nil {
+		return nil, repository.MapGormError(err, "repository.CacheInvalidation.GetDue")
+	}
+
+	return invalidations, nil
+}
+
+func (r *cacheInvalidationRepo) RecordAttempt(ctx context.Context, id uuid.UUID, nextAttemptAt time.Time, attemptErr string) error {
+	err := r.db.
+		WithContext(ctx).
+		Model(&domain.CacheInvalidation{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]any{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"next_attempt_at": nextAttemptAt,
+			"last_error":      attemptErr,
+		}).Error
+	if err != nil {
+		return repository.MapGormError(err, "repository.CacheInvalidation.RecordAttempt")
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package city
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/internal/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type cityRepo struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) domain.CityRepository {
+	return &cityRepo{db: db}
+}
+
+func (r *cityRepo) Create(ctx context.Context, city *domain.City) error {
+	result := r.db.
+		WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(city)
+	if result.Error != nil {
+		return repository.MapGormError(result.Error, "repository.City.Create")
+	}
+	if result.RowsAffected == 0 {
+		return repository.MapGormError(gorm.ErrDuplicatedKey, "repository.City.Create")
+	}
+
+	return nil
+}
+
+func (r *cityRepo) Update(ctx context.Context, city *domain.City) error {
+	err := r.db.
+		WithContext(ctx).
+		Save(city).Error
+	if err != nil {
+		return repository.MapGormError(err, "repository.City.Update")
+	}
+
+	return nil
+}
+
+func (r *cityRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.City, error) {
+	var city domain.City
+
+	err := r.db.
+		WithContext(ctx).
+		Take(&city, "id = ?", id).
+		Error
+	if err != nil {
+		return nil, repository.MapGormError(err, "repository.City.GetByID")
+	}
+
+	return &city, nil
+}
+
+func (r *cityRepo) GetByProviderID(ctx context.Context, providerID string) (*domain.City, error) {
+	var city domain.City
+
+	err := r.db.
+		WithContext(ctx).
+		Take(&city, "provider_id = ?", providerID).
+		Error
+	if err != nil {
+		return nil, repository.MapGormError(err, "repository.City.GetByProviderID")
+	}
+
+	return &city, nil
+}
+
+func (r *cityRepo) FindByName(ctx context.Context, name, country string) ([]domain.City, error) {
+	name = domain.NormalizeCityName(name)
+
+	alias, err := json.Marshal([]string{name})
+	if err != nil {
+		return nil, repository.MapGormError(err, "repository.City.FindByName")
+	}
+
+	query := r.db.
+		WithContext(ctx).
+		Where("normalized_name = ? OR aliases @> ?::jsonb", name, string(alias))
+	if country != "" {
+		query = query.Where("country = ?", country)
+	}
+
+	var cities []domain.City
+	if err := query.Order("created_at ASC").Find(&cities).Error; err != nil {
+		return nil, repository.MapGormError(err, "repository.City.FindByName")
+	}
+
+	return cities, nil
+}
@@ -37,12 +37,32 @@ func InitDB(dsn string) (*gorm.DB, error) {
 	// 3. Run Auto-Migrations
 	log.Println("Running database migrations...")
 	err = db.AutoMigrate(
+		&domain.City{},
 		&domain.Weather{},
+		&domain.APIKey{},
+		&domain.Forecast{},
+		&domain.ForecastStep{},
+		&domain.Place{},
+		&domain.PlaceName{},
+		&domain.WatchlistEntry{},
+		&domain.CacheInvalidation{},
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
 
+	if err := backfillCities(db); err != nil {
+		return nil, err
+	}
+
+	if err := uniqueCityNames(db); err != nil {
+		return nil, err
+	}
+
+	if err := indexPlaceNames(db); err != nil {
+		return nil, err
+	}
+
 	log.Println("Database migration completed successfully")
 	return db, nil
 }
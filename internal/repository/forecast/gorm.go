@@ -0,0 +1,47 @@
+package forecast
+
+import (
+	"context"
+
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/internal/repository"
+	"gorm.io/gorm"
+)
+
+type forecastRepo struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) domain.ForecastRepository {
+	return &forecastRepo{db: db}
+}
+
+// Create stores the run together with its steps in one transaction.
+func (r *forecastRepo) Create(ctx context.Context, forecast *domain.Forecast) error {
+	err := r.db.
+		WithContext(ctx).
+		Create(forecast).Error
+	if err != nil {
+		return repository.MapGormError(err, "repository.Forecast.Create")
+	}
+
+	return nil
+}
+
+func (r *forecastRepo) GetLatest(ctx context.Context, cityName, country string, units domain.Unit) (*domain.Forecast, error) {
+	var forecast domain.Forecast
+
+	err := r.db.
+		WithContext(ctx).
+		Preload("Steps", func(db *gorm.DB) *gorm.DB {
+			return db.Order("valid_at ASC")
+		}).
+		Where("city_name = ? AND country = ? AND unit = ?", cityName, country, units).
+		Order("fetched_at DESC").
+		First(&forecast).Error
+	if err != nil {
+		return nil, repository.MapGormError(err, "repository.Forecast.GetLatest")
+	}
+
+	return &forecast, nil
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// insufficientPrivilege is the SQLSTATE Postgres reports when the role lacks a privilege.
+const insufficientPrivilege = "42501"
+
+// backfillCities creates a city for every distinct city_name/country pair of weather rows that
+// predate the cities table, and links the rows to it. It's a no-op once every named row is linked.
+func backfillCities(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`
+			INSERT INTO cities (id, name, normalized_name, country, coord, created_at, updated_at)
+			SELECT gen_random_uuid(), initcap(w.city_name), lower(w.city_name), w.country,
+				(SELECT w2.coord FROM weathers w2
+					WHERE w2.city_name = w.city_name AND w2.country = w.country AND w2.coord IS NOT NULL
+					ORDER BY w2.fetched_at DESC LIMIT 1),
+				now(), now()
+			FROM (
+				SELECT DISTINCT city_name, country FROM weathers
+				WHERE city_id IS NULL AND city_name <> ''
+			) w
+			WHERE NOT EXISTS (
+				SELECT 1 FROM cities c
+				WHERE c.normalized_name = lower(w.city_name) AND c.country = w.country
+			)`).Error
+		if err != nil {
+			return fmt.Errorf("failed to backfill cities: %w", err)
+		}
+
+		err = tx.Exec(`
+			UPDATE weathers w SET city_id = (
+				SELECT c.id FROM cities c
+				WHERE c.normalized_name = lower(w.city_name) AND c.country = w.country
+				ORDER BY c.created_at LIMIT 1
+			)
+			WHERE w.city_id IS NULL AND w.city_name <> ''`).Error
+		if err != nil {
+			return fmt.Errorf("failed to link weather records to cities: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// uniqueCityNames makes a canonical name unique within a country, so that concurrent first
+// sightings of a city can't create it twice. Duplicates created before are merged into the
+// oldest city first: their weather records are relinked and their aliases kept.
+func uniqueCityNames(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`
+			CREATE TEMP TABLE city_duplicates ON COMMIT DROP AS
+			SELECT c.id, first_value(c.id) OVER (
+				PARTITION BY c.normalized_name, c.country ORDER BY c.created_at, c.id
+			) AS keep_id
+			FROM cities c`).Error
+		if err != nil {
+			return fmt.Errorf("failed to find duplicate cities: %w", err)
+		}
+
+		err = tx.Exec(`
+			UPDATE weathers w SET city_id = d.keep_id
+			FROM city_duplicates d
+			WHERE w.city_id = d.id AND d.id <> d.keep_id`).Error
+		if err != nil {
+			return fmt.Errorf("failed to relink weather records of duplicate cities: %w", err)
+		}
+
+		err = tx.Exec(`
+			UPDATE cities c SET aliases = (
+				SELECT jsonb_agg(DISTINCT a) FROM (
+					SELECT jsonb_array_elements_text(coalesce(c2.aliases, '[]'::jsonb)) AS a
+					FROM city_duplicates d JOIN cities c2 ON c2.id = d.id
+					WHERE d.keep_id = c.id
+				) aliases
+			)
+			WHERE c.id IN (SELECT keep_id FROM city_duplicates WHERE id <> keep_id)`).Error
+		if err != nil {
+			return fmt.Errorf("failed to merge aliases of duplicate cities: %w", err)
+		}
+
+		err = tx.Exec(`DELETE FROM cities WHERE id IN (SELECT id FROM city_duplicates WHERE id <> keep_id)`).Error
+		if err != nil {
+			return fmt.Errorf("failed to delete duplicate cities: %w", err)
+		}
+
+		err = tx.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_cities_name_country ON cities (normalized_name, country)").Error
+		if err != nil {
+			return fmt.Errorf("failed to index city names: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// indexPlaceNames creates the trigram index that place search relies on for both prefix and
+// fuzzy matching. AutoMigrate can't express operator classes, hence the raw SQL. A role that
+// may not create extensions doesn't keep the service from starting: the index is skipped, and
+// place search fails until pg_trgm is enabled by someone who may.
+func indexPlaceNames(db *gorm.DB) error {
+	err := db.Exec("CREATE EXTENSION IF NOT EXISTS pg_trgm").Error
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == insufficientPrivilege {
+		log.Printf("Skipping place name index, the database role can't enable pg_trgm: %v", err)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to enable pg_trgm: %w", err)
+	}
+
+	err = db.Exec("CREATE INDEX IF NOT EXISTS idx_place_names_trgm ON place_names USING gin (name gin_trgm_ops)").Error
+	if err != nil {
+		return fmt.Errorf("failed to index place names: %w", err)
+	}
+
+	return nil
+}
@@ -52,29 +52,59 @@ func (_m *WeatherRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return r0
 }
 
-// GetAll provides a mock function with given fields: ctx
-func (_m *WeatherRepository) GetAll(ctx context.Context) ([]domain.Weather, error) {
-	ret := _m.Called(ctx)
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *WeatherRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Weather, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *domain.Weather
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*domain.Weather, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *domain.Weather); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Weather)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHistory provides a mock function with given fields: ctx, query, limit
+func (_m *WeatherRepository) GetHistory(ctx context.Context, query domain.WeatherHistoryQuery, limit int) ([]domain.Weather, error) {
+	ret := _m.Called(ctx, query, limit)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetAll")
+		panic("no return value specified for GetHistory")
 	}
 
 	var r0 []domain.Weather
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.Weather, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.WeatherHistoryQuery, int) ([]domain.Weather, error)); ok {
+		return rf(ctx, query, limit)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []domain.Weather); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.WeatherHistoryQuery, int) []domain.Weather); ok {
+		r0 = rf(ctx, query, limit)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Weather)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, domain.WeatherHistoryQuery, int) error); ok {
+		r1 = rf(ctx, query, limit)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -82,21 +112,51 @@ func (_m *WeatherRepository) GetAll(ctx context.Context) ([]domain.Weather, erro
 	return r0, r1
 }
 
-// GetByID provides a mock function with given fields: ctx, id
-func (_m *WeatherRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Weather, error) {
-	ret := _m.Called(ctx, id)
+// GetHistoryBuckets provides a mock function with given fields: ctx, query
+func (_m *WeatherRepository) GetHistoryBuckets(ctx context.Context, query domain.WeatherHistoryQuery) ([]domain.WeatherBucket, error) {
+	ret := _m.Called(ctx, query)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetByID")
+		panic("no return value specified for GetHistoryBuckets")
+	}
+
+	var r0 []domain.WeatherBucket
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.WeatherHistoryQuery) ([]domain.WeatherBucket, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.WeatherHistoryQuery) []domain.WeatherBucket); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.WeatherBucket)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.WeatherHistoryQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLatestByCity provides a mock function with given fields: ctx, cityID
+func (_m *WeatherRepository) GetLatestByCity(ctx context.Context, cityID uuid.UUID) (*domain.Weather, error) {
+	ret := _m.Called(ctx, cityID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLatestByCity")
 	}
 
 	var r0 *domain.Weather
 	var r1 error
 	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*domain.Weather, error)); ok {
-		return rf(ctx, id)
+		return rf(ctx, cityID)
 	}
 	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *domain.Weather); ok {
-		r0 = rf(ctx, id)
+		r0 = rf(ctx, cityID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.Weather)
@@ -104,7 +164,7 @@ func (_m *WeatherRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = rf(ctx, id)
+		r1 = rf(ctx, cityID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -112,29 +172,29 @@ func (_m *WeatherRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain
 	return r0, r1
 }
 
-// GetLatestByCity provides a mock function with given fields: ctx, cityName
-func (_m *WeatherRepository) GetLatestByCity(ctx context.Context, cityName string) (*domain.Weather, error) {
-	ret := _m.Called(ctx, cityName)
+// List provides a mock function with given fields: ctx, query
+func (_m *WeatherRepository) List(ctx context.Context, query domain.WeatherQuery) (*domain.WeatherPage, error) {
+	ret := _m.Called(ctx, query)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetLatestByCity")
+		panic("no return value specified for List")
 	}
 
-	var r0 *domain.Weather
+	var r0 *domain.WeatherPage
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.Weather, error)); ok {
-		return rf(ctx, cityName)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.WeatherQuery) (*domain.WeatherPage, error)); ok {
+		return rf(ctx, query)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.Weather); ok {
-		r0 = rf(ctx, cityName)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.WeatherQuery) *domain.WeatherPage); ok {
+		r0 = rf(ctx, query)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*domain.Weather)
+			r0 = ret.Get(0).(*domain.WeatherPage)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, cityName)
+	if rf, ok := ret.Get(1).(func(context.Context, domain.WeatherQuery) error); ok {
+		r1 = rf(ctx, query)
 	} else {
 		r1 = ret.Error(1)
 	}
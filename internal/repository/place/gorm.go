@@ -0,0 +1,116 @@
+package place
+
+import (
+	"context"
+	"strings"
+
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/internal/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type placeRepo struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) domain.PlaceRepository {
+	return &placeRepo{db: db}
+}
+
+func (r *placeRepo) Upsert(ctx context.Context, places []domain.Place) error {
+	if len(places) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(places))
+	var names []domain.PlaceName
+	for i, place := range places {
+		ids[i] = place.ID
+		names = append(names, place.Names...)
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Omit("Names").
+			Clauses(clause.OnConflict{UpdateAll: true}).
+			Create(&places).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("place_id IN ?", ids).Delete(&domain.PlaceName{}).Error; err != nil {
+			return err
+		}
+
+		if len(names) == 0 {
+			return nil
+		}
+
+		return tx.
+			Clauses(clause.OnConflict{DoNothing: true}).
+			CreateInBatches(names, 1000).Error
+	})
+	if err != nil {
+		return repository.MapGormError(err, "repository.Place.Upsert")
+	}
+
+	return nil
+}
+
+func (r *placeRepo) Search(ctx context.Context, query, country string, limit int) ([]domain.Place, error) {
+	query = domain.NormalizeCityName(query)
+	prefix := escapeLike(query) + "%"
+
+	// The trigram index on place_names.name serves both the prefix LIKE and the similarity (%)
+	// operator; a place matches once however many of its names do.
+	matches := r.db.
+		Model(&domain.PlaceName{}).
+		Select("place_id, bool_or(name LIKE ?) AS prefix, max(similarity(name, ?)) AS score", prefix, query).
+		Where("name LIKE ? OR name % ?", prefix, query).
+		Group("place_id")
+
+	q := r.db.
+		WithContext(ctx).
+		Joins("JOIN (?) m ON m.place_id = places.id", matches)
+	if country != "" {
+		q = q.Where("places.country = ?", country)
+	}
+
+	var places []domain.Place
+	err := q.
+		Order("m.prefix DESC, places.population DESC, m.score DESC").
+		Limit(limit).
+		Find(&places).Error
+	if err != nil {
+		return nil, repository.MapGormError(err, "repository.Place.Search")
+	}
+
+	return places, nil
+}
+
+func (r *placeRepo) Exists(ctx context.Context, name, country string) (bool, error) {
+	q := r.db.
+		WithContext(ctx).
+		Model(&domain.PlaceName{}).
+		Joins("JOIN places ON places.id = place_names.place_id").
+		Where("place_names.name = ?", domain.NormalizeCityName(name))
+	if country != "" {
+		q = q.Where("places.country = ?", country)
+	}
+
+	var count int64
+	err := q.Count(&count).Error
+	if err != nil {
+		return false, repository.MapGormError(err, "repository.Place.Exists")
+	}
+
+	return count > 0, nil
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike escapes the LIKE wildcards in s, so that user input only ever matches literally.
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
@@ -0,0 +1,142 @@
+package watchlist
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/internal/repository"
+	"gorm.io/gorm"
+)
+
+type watchlistRepo struct {
+	db *gorm.DB
+}
+
+func New(db *gorm.DB) domain.WatchlistRepository {
+	return &watchlistRepo{db: db}
+}
+
+func (r *watchlistRepo) Create(ctx context.Context, entry *domain.WatchlistEntry) error {
+	err := r.db.
+		WithContext(ctx).
+		Create(entry).Error
+	if err != nil {
+		return repository.MapGormError(err, "repository.Watchlist.Create")
+	}
+
+	return nil
+}
+
+func (r *watchlistRepo) Update(ctx context.Context, entry *domain.WatchlistEntry) error {
+	err := r.db.
+		WithContext(ctx).
+		Save(entry).Error
+	if err != nil {
+		return repository.MapGormError(err, "repository.Watchlist.Update")
+	}
+
+	return nil
+}
+
+func (r *watchlistRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	result := r.db.
+		WithContext(ctx).
+		Delete(&domain.WatchlistEntry{}, "id = ?", id)
+	if result.Error != nil {
+		return repository.MapGormError(result.Error, "repository.Watchlist.Delete")
+	}
+	if result.RowsAffected == 0 {
+		return repository.MapGormError(gorm.ErrRecordNotFound, "repository.Watchlist.Delete")
+	}
+
+	return nil
+}
+
+func (r *watchlistRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.WatchlistEntry, error) {
+	var entry domain.WatchlistEntry
+
+	err := r.db.
+		WithContext(ctx).
+		Take(&entry, "id = ?", id).
+		Error
+	if err != nil {
+		return nil, repository.MapGormError(err, "repository.Watchlist.GetByID")
+	}
+
+	return &entry, nil
+}
+
+func (r *watchlistRepo) GetAll(ctx context.Context) ([]domain.WatchlistEntry, error) {
+	var entries []domain.WatchlistEntry
+
+	err := r.db.
+		WithContext(ctx).
+		Order("created_at ASC").
+		Find(&entries).Error
+	if err != nil {
+		return nil, repository.MapGormError(err, "repository.Watchlist.GetAll")
+	}
+
+	return entries, nil
+}
+
+func (r *watchlistRepo) GetDue(ctx context.Context, now time.Time, limit int) ([]domain.WatchlistEntry, error) {
+	var entries []domain.WatchlistEntry
+
+	err := r.db.
+		WithContext(ctx).
+		Where("enabled AND next_run_at <= ?", now).
+		Order("next_run_at ASC").
+		Limit(limit).
+		Find(&entries).Error
+	if err != nil {
+		return nil, repository.MapGormError(err, "repository.Watchlist.GetDue")
+	}
+
+	return entries, nil
+}
+
+func (r *watchlistRepo) Claim(ctx context.Context, id uuid.UUID, dueAt, nextRunAt time.Time, token int64) (bool, error) {
+	result := r.db.
+		WithContext(ctx).
+		Model(&domain.WatchlistEntry{}).
+		Where("id = ? AND next_run_at = ?", id, dueAt).
+		Scopes(fenced(token)).
+		UpdateColumns(map[string]any{
+			"next_run_at": nextRunAt,
+			"fence_token": gorm.Expr("GREATEST(fence_token, ?)", token),
+		})
+	if result.Error != nil {
+		return false, repository.MapGormError(result.Error, "repository.Watchlist.Claim")
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (r *watchlistRepo) RecordRun(ctx context.Context, id uuid.UUID, ranAt time.Time, runErr string, token int64) error {
+	err := r.db.
+		WithContext(ctx).
+		Model(&domain.WatchlistEntry{}).
+		Where("id = ?", id).
+		Scopes(fenced(token)).
+		UpdateColumns(map[string]any{"last_run_at": ranAt, "last_error": runErr}).Error
+	if err != nil {
+		return repository.MapGormError(err, "repository.Watchlist.RecordRun")
+	}
+
+	return nil
+}
+
+// fenced refuses writes carrying a fencing token older than the entry's. A zero token isn't
+// fenced.
+func fenced(token int64) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if token == 0 {
+			return db
+		}
+
+		return db.Where("fence_token <= ?", token)
+	}
+}
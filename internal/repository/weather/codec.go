@@ -0,0 +1,99 @@
+package weather
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ugorji/go/codec"
+)
+
+// cacheFormatVersion is the first byte of every cached value. Bump it whenever the cached
+// types change in a way a codec can't read across, so that entries written before are ignored
+// instead of misread.
+const cacheFormatVersion byte = 1
+
+// Codec encodes the values the proxy caches.
+type Codec interface {
+	// Name identifies the codec in configuration.
+	Name() string
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// Codecs by their IDs, the second byte of every cached value, which tells entries written with
+// another codec apart after the configured codec changes. IDs must never be reused: 3 belonged
+// to a protobuf codec that was removed.
+var codecIDs = map[string]byte{
+	JSONCodec{}.Name():    1,
+	MsgpackCodec{}.Name(): 2,
+}
+
+// CodecByName returns the codec named json or msgpack.
+func CodecByName(name string) (Codec, error) {
+	switch name {
+	case JSONCodec{}.Name():
+		return JSONCodec{}, nil
+	case MsgpackCodec{}.Name():
+		return MsgpackCodec{}, nil
+	default:
+		return nil, fmt.Errorf("unknown cache codec %q", name)
+	}
+}
+
+var errStaleFormat = errors.New("cached value was written in another format")
+
+// encode marshals v with c behind the format version and codec ID.
+func encode(c Codec, v any) ([]byte, error) {
+	data, err := c.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte{cacheFormatVersion, codecIDs[c.Name()]}, data...), nil
+}
+
+// decode unmarshals a value encoded with c, failing with errStaleFormat for values written in
+// another format version or with another codec.
+func decode(c Codec, data []byte, v any) error {
+	if len(data) < 2 || data[0] != cacheFormatVersion || data[1] != codecIDs[c.Name()] {
+		return errStaleFormat
+	}
+
+	return c.Unmarshal(data[2:], v)
+}
+
+type JSONCodec struct{}
+
+func (JSONCodec) Name() string {
+	return "json"
+}
+
+func (JSONCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// msgpackHandle is safe for concurrent use once configured.
+var msgpackHandle = &codec.MsgpackHandle{WriteExt: true}
+
+// MsgpackCodec encodes values as MessagePack maps keyed like their JSON encoding.
+type MsgpackCodec struct{}
+
+func (MsgpackCodec) Name() string {
+	return "msgpack"
+}
+
+func (MsgpackCodec) Marshal(v any) ([]byte, error) {
+	var data []byte
+	err := codec.NewEncoderBytes(&data, msgpackHandle).Encode(v)
+
+	return data, err
+}
+
+func (MsgpackCodec) Unmarshal(data []byte, v any) error {
+	return codec.NewDecoderBytes(data, msgpackHandle).Decode(v)
+}
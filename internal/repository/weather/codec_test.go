@@ -0,0 +1,312 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xoltawn/weatherhub/internal/domain"
+)
+
+var codecs = []Codec{JSONCodec{}, MsgpackCodec{}}
+
+func sampleWeather() *domain.Weather {
+	cityID := uuid.New()
+	providerID := "openweathermap:2643743"
+	fetchedAt := time.Date(2026, 3, 1, 12, 30, 0, 123456789, time.UTC)
+
+	return &domain.Weather{
+		ID:     uuid.New(),
+		CityID: &cityID,
+		City: &domain.City{
+			ID:         cityID,
+			ProviderID: &providerID,
+			Name:       "London",
+			Country:    "gb",
+			Coord:      &domain.Coordinates{Lat: 51.5085, Lon: -0.1257},
+			Timezone:   "Europe/London",
+			Aliases:    []string{"londres", "londra"},
+			CreatedAt:  fetchedAt.Add(-24 * time.Hour),
+			UpdatedAt:  fetchedAt.Add(-time.Hour),
+		},
+		CityName:    "londres",
+		Country:     "gb",
+		Temperature: 12.5,
+		Unit:        domain.Metric,
+		Description: "light rain",
+		Humidity:    81,
+		WindSpeed:   4.6,
+		Provider:    "openweathermap",
+		Coord:       &domain.Coordinates{Lat: 51.5085, Lon: -0.1257},
+		FetchedAt:   fetchedAt,
+		CreatedAt:   fetchedAt,
+		UpdatedAt:   fetchedAt,
+	}
+}
+
+// assertSameJSON compares values the way API clients see them.
+func assertSameJSON(t *testing.T, want, got any) {
+	t.Helper()
+
+	wantJSON, err := json.Marshal(want)
+	require.NoError(t, err)
+	gotJSON, err := json.Marshal(got)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, string(wantJSON), string(gotJSON))
+}
+
+func TestCodec_RoundTrip(t *testing.T) {
+	for _, codec := range codecs {
+		t.Run(codec.Name(), func(t *testing.T) {
+			t.Run("record", func(t *testing.T) {
+				// Arrange
+				entry := &cacheEntry[domain.Weather]{Value: sampleWeather(), ExpiresAt: 1772368200000, LoadTime: 3 * time.Millisecond}
+
+				// Act
+				data, err := encode(codec, entry)
+				require.NoError(t, err)
+
+				var got cacheEntry[domain.Weather]
+				err = decode(codec, data, &got)
+
+				// Assert
+				require.NoError(t, err)
+				assert.Equal(t, entry.ExpiresAt, got.ExpiresAt)
+				assert.Equal(t, entry.LoadTime, got.LoadTime)
+				assert.True(t, entry.Value.FetchedAt.Equal(got.Value.FetchedAt))
+				assertSameJSON(t, entry.Value, got.Value)
+			})
+
+			t.Run("sparse-record", func(t *testing.T) {
+				entry := &cacheEntry[domain.Weather]{Value: &domain.Weather{ID: uuid.New(), CityName: "atlantis", Temperature: -3}}
+
+				data, err := encode(codec, entry)
+				require.NoError(t, err)
+
+				var got cacheEntry[domain.Weather]
+				require.NoError(t, decode(codec, data, &got))
+				assert.Nil(t, got.Value.CityID)
+				assert.Nil(t, got.Value.City)
+				assert.Nil(t, got.Value.Coord)
+				assert.True(t, got.Value.FetchedAt.IsZero())
+				assert.Equal(t, -3.0, got.Value.Temperature)
+			})
+
+			t.Run("not-found", func(t *testing.T) {
+				entry := &cacheEntry[domain.Weather]{NotFound: true, ExpiresAt: 1772368200000}
+
+				data, err := encode(codec, entry)
+				require.NoError(t, err)
+
+				var got cacheEntry[domain.Weather]
+				require.NoError(t, decode(codec, data, &got))
+				assert.True(t, got.NotFound)
+				assert.Nil(t, got.Value)
+				assert.Equal(t, entry.ExpiresAt, got.ExpiresAt)
+			})
+
+			t.Run("page", func(t *testing.T) {
+				page := &domain.WeatherPage{Items: []domain.Weather{*sampleWeather(), *sampleWeather()}, NextCursor: "eyJzIjoi"}
+				entry := &cacheEntry[domain.WeatherPage]{Value: page, ExpiresAt: 1772368200000}
+
+				data, err := encode(codec, entry)
+				require.NoError(t, err)
+
+				var got cacheEntry[domain.WeatherPage]
+				require.NoError(t, decode(codec, data, &got))
+				require.Len(t, got.Value.Items, 2)
+				assert.Equal(t, page.NextCursor, got.Value.NextCursor)
+				assert.Equal(t, page.Items[1].ID, got.Value.Items[1].ID)
+			})
+		})
+	}
+}
+
+// fillFields sets every exported field of v, recursively, to a distinct non-zero value, so that
+// a field a codec leaves out shows up as a difference. Fields kept out of JSON aren't cached and
+// stay zero. Times are in UTC, the location every codec decodes them in.
+func fillFields(v reflect.Value, n *int) {
+	*n++
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		v.Set(reflect.New(v.Type().Elem()))
+		fillFields(v.Elem(), n)
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(v.Type(), 2, 2))
+		for i := range v.Len() {
+			fillFields(v.Index(i), n)
+		}
+	case reflect.Struct:
+		if v.Type() == reflect.TypeFor[time.Time]() {
+			v.Set(reflect.ValueOf(time.Date(2026, 3, 1, 12, 0, *n, *n*1000, time.UTC)))
+			return
+		}
+
+		for i := range v.NumField() {
+			field := v.Type().Field(i)
+			if field.IsExported() && field.Tag.Get("json") != "-" {
+				fillFields(v.Field(i), n)
+			}
+		}
+	case reflect.Array:
+		for i := range v.Len() {
+			v.Index(i).SetUint(uint64(*n + i))
+		}
+	case reflect.String:
+		v.SetString(fmt.Sprintf("s%d", *n))
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(*n))
+	case reflect.Uint8, reflect.Uint32, reflect.Uint64:
+		v.SetUint(uint64(*n))
+	case reflect.Float64:
+		v.SetFloat(float64(*n) + 0.5)
+	case reflect.Bool:
+		v.SetBool(true)
+	default:
+		panic(fmt.Sprintf("fillFields: unsupported kind %s", v.Kind()))
+	}
+}
+
+// TestCodec_AllFields guards against a field added to the cached types that a codec drops, by
+// requiring every field to survive a round trip.
+func TestCodec_AllFields(t *testing.T) {
+	for _, codec := range codecs {
+		t.Run(codec.Name(), func(t *testing.T) {
+			t.Run("record", func(t *testing.T) {
+				// Arrange
+				var entry cacheEntry[domain.Weather]
+				fillFields(reflect.ValueOf(&entry.Value).Elem(), new(int))
+				entry.ExpiresAt, entry.LoadTime = 1772368200000, 3*time.Millisecond
+
+				// Act
+				data, err := encode(codec, &entry)
+				require.NoError(t, err)
+
+				var got cacheEntry[domain.Weather]
+				err = decode(codec, data, &got)
+
+				// Assert
+				require.NoError(t, err)
+				assert.True(t, reflect.DeepEqual(entry, got), "round trip differs:\nwant %+v\ngot  %+v", entry.Value, got.Value)
+			})
+
+			t.Run("page", func(t *testing.T) {
+				var entry cacheEntry[domain.WeatherPage]
+				fillFields(reflect.ValueOf(&entry.Value).Elem(), new(int))
+				entry.ExpiresAt, entry.LoadTime = 1772368200000, 3*time.Millisecond
+
+				data, err := encode(codec, &entry)
+				require.NoError(t, err)
+
+				var got cacheEntry[domain.WeatherPage]
+				require.NoError(t, decode(codec, data, &got))
+				assert.True(t, reflect.DeepEqual(entry, got), "round trip differs:\nwant %+v\ngot  %+v", entry.Value, got.Value)
+			})
+		})
+	}
+}
+
+func TestCodec_StaleFormat(t *testing.T) {
+	entry := &cacheEntry[domain.Weather]{Value: sampleWeather()}
+	data, err := encode(MsgpackCodec{}, entry)
+	require.NoError(t, err)
+
+	legacy, err := json.Marshal(entry.Value)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		codec Codec
+		data  []byte
+	}{
+		{name: "other-codec", codec: JSONCodec{}, data: data},
+		{name: "other-version", codec: MsgpackCodec{}, data: append([]byte{cacheFormatVersion + 1}, data[1:]...)},
+		{name: "unversioned", codec: JSONCodec{}, data: legacy},
+		{name: "empty", codec: JSONCodec{}, data: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got cacheEntry[domain.Weather]
+
+			assert.ErrorIs(t, decode(tt.codec, tt.data, &got), errStaleFormat)
+		})
+	}
+}
+
+func TestCachedWeatherRepo_StaleFormatIsAMiss(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	cache := newFakeCache()
+	want := sampleWeather()
+	real := &slowRepo{weather: want}
+	repo := newCachedWeatherRepo(real, cache, time.Hour, WithCodec(MsgpackCodec{}))
+
+	// A record cached with JSON before the codec was switched.
+	cacheWeather(t, cache, want, time.Now().Add(time.Hour), time.Millisecond)
+
+	// Act
+	got, err := repo.GetByID(ctx, want.ID)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, want.ID, got.ID)
+	assert.EqualValues(t, 1, real.calls.Load())
+
+	entry, ok := get[domain.Weather](ctx, repo, repo.fmtKey(want.ID))
+	require.True(t, ok, "the record is cached again with the new codec")
+	assert.Equal(t, want.ID, entry.Value.ID)
+}
+
+func TestCodecByName(t *testing.T) {
+	for _, codec := range codecs {
+		got, err := CodecByName(codec.Name())
+		require.NoError(t, err)
+		assert.Equal(t, codec, got)
+	}
+
+	_, err := CodecByName("gob")
+	assert.Error(t, err)
+}
+
+// BenchmarkCodec compares the codecs on a typical cached record, with its city. The bytes/value
+// metric is the size of the value stored in Redis.
+func BenchmarkCodec(b *testing.B) {
+	entry := &cacheEntry[domain.Weather]{Value: sampleWeather(), ExpiresAt: 1772368200000, LoadTime: 3 * time.Millisecond}
+
+	for _, codec := range codecs {
+		data, err := encode(codec, entry)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(fmt.Sprintf("%s/marshal", codec.Name()), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := encode(codec, entry); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(len(data)), "bytes/value")
+		})
+
+		b.Run(fmt.Sprintf("%s/unmarshal", codec.Name()), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				var got cacheEntry[domain.Weather]
+				if err := decode(codec, data, &got); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(len(data)), "bytes/value")
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package weather
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xoltawn/weatherhub/internal/domain"
+)
+
+// sortColumns maps the sortable fields to their columns. Only whitelisted fields ever reach SQL.
+var sortColumns = map[domain.WeatherSortField]string{
+	domain.SortByFetchedAt:   "weathers.fetched_at",
+	domain.SortByTemperature: "weathers.temperature",
+	domain.SortByCityName:    "weathers.city_name",
+}
+
+// cursor is the keyset position after the last record of a page: its sort key, with the ID to
+// break ties. Sort records the order it was issued for, since the key means nothing in another.
+type cursor struct {
+	Sort  string          `json:"s"`
+	Value json.RawMessage `json:"v"`
+	ID    uuid.UUID       `json:"id"`
+}
+
+func encodeCursor(sort domain.WeatherSort, last *domain.Weather) (string, error) {
+	var value any
+	switch sort.Field {
+	case domain.SortByFetchedAt:
+		value = last.FetchedAt
+	case domain.SortByTemperature:
+		value = last.Temperature
+	case domain.SortByCityName:
+		value = last.CityName
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(cursor{Sort: sort.String(), Value: raw, ID: last.ID})
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor returns the sort key and ID a cursor issued for sort points after.
+func decodeCursor(s string, sort domain.WeatherSort) (any, uuid.UUID, error) {
+	invalid := fmt.Errorf("invalid cursor: %w", domain.ErrInvalidInput)
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, uuid.Nil, invalid
+	}
+
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, uuid.Nil, invalid
+	}
+	if c.Sort != sort.String() {
+		return nil, uuid.Nil, fmt.Errorf("cursor was issued for sort %q, not %q: %w", c.Sort, sort, domain.ErrInvalidInput)
+	}
+
+	value, err := decodeSortValue(sort.Field, c.Value)
+	if err != nil {
+		return nil, uuid.Nil, invalid
+	}
+
+	return value, c.ID, nil
+}
+
+func decodeSortValue(field domain.WeatherSortField, raw json.RawMessage) (any, error) {
+	switch field {
+	case domain.SortByFetchedAt:
+		var t time.Time
+		err := json.Unmarshal(raw, &t)
+		return t, err
+	case domain.SortByTemperature:
+		var f float64
+		err := json.Unmarshal(raw, &f)
+		return f, err
+	default:
+		var s string
+		err := json.Unmarshal(raw, &s)
+		return s, err
+	}
+}
@@ -0,0 +1,64 @@
+package weather
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xoltawn/weatherhub/internal/domain"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	last := &domain.Weather{
+		ID:          uuid.New(),
+		CityName:    "london",
+		Temperature: 12.5,
+		FetchedAt:   time.Date(2026, 3, 1, 12, 30, 0, 123456000, time.UTC),
+	}
+
+	tests := []struct {
+		sort domain.WeatherSort
+		want any
+	}{
+		{sort: domain.WeatherSort{Field: domain.SortByFetchedAt, Desc: true}, want: last.FetchedAt},
+		{sort: domain.WeatherSort{Field: domain.SortByTemperature}, want: 12.5},
+		{sort: domain.WeatherSort{Field: domain.SortByCityName, Desc: true}, want: "london"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sort.String(), func(t *testing.T) {
+			// Act
+			encoded, err := encodeCursor(tt.sort, last)
+			require.NoError(t, err)
+
+			value, id, err := decodeCursor(encoded, tt.sort)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, value)
+			assert.Equal(t, last.ID, id)
+		})
+	}
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	sort := domain.WeatherSort{Field: domain.SortByFetchedAt, Desc: true}
+
+	t.Run("garbage", func(t *testing.T) {
+		_, _, err := decodeCursor("not a cursor!", sort)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+
+	t.Run("other-sort-order", func(t *testing.T) {
+		encoded, err := encodeCursor(domain.WeatherSort{Field: domain.SortByFetchedAt}, &domain.Weather{ID: uuid.New()})
+		require.NoError(t, err)
+
+		_, _, err = decodeCursor(encoded, sort)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		assert.ErrorContains(t, err, `issued for sort "fetched_at"`)
+	})
+}
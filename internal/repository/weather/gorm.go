@@ -2,6 +2,7 @@ package weather
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/google/uuid"
 	"github.com/xoltawn/weatherhub/internal/domain"
@@ -28,17 +29,73 @@ func (r *weatherRepo) Create(ctx context.Context, weather *domain.Weather) error
 	return err
 }
 
-func (r *weatherRepo) GetAll(ctx context.Context) ([]domain.Weather, error) {
-	var records []domain.Weather
+func (r *weatherRepo) List(ctx context.Context, query domain.WeatherQuery) (*domain.WeatherPage, error) {
+	column, ok := sortColumns[query.Sort.Field]
+	if !ok {
+		return nil, fmt.Errorf("cannot sort by %q: %w", query.Sort.Field, domain.ErrInvalidInput)
+	}
 
-	err := r.db.
+	q := r.db.
 		WithContext(ctx).
+		Preload("City")
+
+	if query.CityID != nil {
+		q = q.Where("weathers.city_id = ?", *query.CityID)
+	}
+	if query.CityName != "" {
+		q = q.Where("weathers.city_name = ?", query.CityName)
+	}
+	if query.Country != "" {
+		q = q.Where("weathers.country = ?", query.Country)
+	}
+	if query.Unit != "" {
+		q = q.Where("weathers.unit = ?", query.Unit)
+	}
+	if query.FetchedFrom != nil {
+		q = q.Where("weathers.fetched_at >= ?", *query.FetchedFrom)
+	}
+	if query.FetchedTo != nil {
+		q = q.Where("weathers.fetched_at < ?", *query.FetchedTo)
+	}
+	if query.MinTemperature != nil {
+		q = q.Where("weathers.temperature >= ?", *query.MinTemperature)
+	}
+	if query.MaxTemperature != nil {
+		q = q.Where("weathers.temperature <= ?", *query.MaxTemperature)
+	}
+
+	direction, after := "ASC", ">"
+	if query.Sort.Desc {
+		direction, after = "DESC", "<"
+	}
+
+	if query.Cursor != "" {
+		value, id, err := decodeCursor(query.Cursor, query.Sort)
+		if err != nil {
+			return nil, err
+		}
+		q = q.Where(fmt.Sprintf("(%s, weathers.id) %s (?, ?)", column, after), value, id)
+	}
+
+	// One extra row tells whether there's a next page.
+	var records []domain.Weather
+	err := q.
+		Order(fmt.Sprintf("%s %s, weathers.id %s", column, direction, direction)).
+		Limit(query.Limit + 1).
 		Find(&records).Error
 	if err != nil {
-		return nil, repository.MapGormError(err, "repository.Weather.GetAll")
+		return nil, repository.MapGormError(err, "repository.Weather.List")
+	}
+
+	page := &domain.WeatherPage{Items: records}
+	if len(records) > query.Limit {
+		page.Items = records[:query.Limit]
+		if page.NextCursor, err = encodeCursor(query.Sort, &page.Items[query.Limit-1]); err != nil {
+			return nil, repository.MapGormError(err, "repository.Weather.List")
+		}
 	}
 
-	return records, err
+	return page, nil
 }
 
 func (r *weatherRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Weather, error) {
@@ -46,6 +103,7 @@ func (r *weatherRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Weathe
 
 	err := r.db.
 		WithContext(ctx).
+		Preload("City").
 		Take(&weather, "id = ?", id).
 		Error
 	if err != nil {
@@ -55,12 +113,13 @@ func (r *weatherRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Weathe
 	return &weather, nil
 }
 
-func (r *weatherRepo) GetLatestByCity(ctx context.Context, cityName string) (*domain.Weather, error) {
+func (r *weatherRepo) GetLatestByCity(ctx context.Context, cityID uuid.UUID) (*domain.Weather, error) {
 	var weather domain.Weather
 
 	err := r.db.
 		WithContext(ctx).
-		Where("city_name = ?", cityName).
+		Preload("City").
+		Where("city_id = ?", cityID).
 		Order("fetched_at DESC").
 		First(&weather).Error
 	if err != nil {
@@ -70,6 +129,46 @@ func (r *weatherRepo) GetLatestByCity(ctx context.Context, cityName string) (*do
 	return &weather, nil
 }
 
+func (r *weatherRepo) GetHistory(ctx context.Context, query domain.WeatherHistoryQuery, limit int) ([]domain.Weather, error) {
+	var records []domain.Weather
+
+	err := r.db.
+		WithContext(ctx).
+		Where("city_id = ? AND unit = ? AND fetched_at >= ? AND fetched_at < ?", query.CityID, query.Unit, query.From, query.To).
+		Order("fetched_at ASC, id ASC").
+		Limit(limit).
+		Find(&records).Error
+	if err != nil {
+		return nil, repository.MapGormError(err, "repository.Weather.GetHistory")
+	}
+
+	return records, nil
+}
+
+func (r *weatherRepo) GetHistoryBuckets(ctx context.Context, query domain.WeatherHistoryQuery) ([]domain.WeatherBucket, error) {
+	var buckets []domain.WeatherBucket
+
+	err := r.db.
+		WithContext(ctx).
+		Model(&domain.Weather{}).
+		Select(`date_bin(make_interval(secs => ?), fetched_at, ?::timestamptz) AS start,
+			count(*) AS count,
+			avg(temperature) AS avg_temperature,
+			min(temperature) AS min_temperature,
+			max(temperature) AS max_temperature,
+			avg(humidity) AS avg_humidity,
+			max(wind_speed) AS max_wind_speed`, query.Interval.Seconds(), domain.HistoryBucketOrigin).
+		Where("city_id = ? AND unit = ? AND fetched_at >= ? AND fetched_at < ?", query.CityID, query.Unit, query.From, query.To).
+		Group("start").
+		Order("start ASC").
+		Scan(&buckets).Error
+	if err != nil {
+		return nil, repository.MapGormError(err, "repository.Weather.GetHistoryBuckets")
+	}
+
+	return buckets, nil
+}
+
 func (r *weatherRepo) Update(ctx context.Context, weather *domain.Weather) error {
 	err := r.db.
 		WithContext(ctx).
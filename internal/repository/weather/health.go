@@ -0,0 +1,95 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/xoltawn/weatherhub/pkg/resilience"
+)
+
+// breakerClient is a cacheClient that stops sending commands to Redis once the breaker opens,
+// failing them right away with resilience.ErrCircuitOpen so that the proxy reads through to the
+// database. Once the breaker's open timeout passes, the next command probes Redis and closes the
+// breaker again if it succeeds.
+type breakerClient struct {
+	next    cacheClient
+	breaker *resilience.Breaker
+
+	// bypassed counts the commands not sent while the breaker was open.
+	bypassed atomic.Uint64
+}
+
+// run sends a command unless the breaker is open, and reports its outcome. Misses and error
+// replies, such as NOSCRIPT, show that Redis is up; commands the caller gave up on don't count.
+func run[C redis.Cmder](ctx context.Context, c *breakerClient, newCmd func(ctx context.Context, args ...any) C, send func() C) C {
+	if err := c.breaker.Allow(); err != nil {
+		c.bypassed.Add(1)
+		cmd := newCmd(ctx)
+		cmd.SetErr(err)
+		return cmd
+	}
+
+	cmd := send()
+	var reply redis.Error
+	switch err := cmd.Err(); {
+	case err == nil, errors.As(err, &reply):
+		c.breaker.Success()
+	case ctx.Err() != nil:
+		c.breaker.Release()
+	default:
+		c.breaker.Failure()
+	}
+
+	return cmd
+}
+
+func (c *breakerClient) Get(ctx context.Context, key string) *redis.StringCmd {
+	return run(ctx, c, redis.NewStringCmd, func() *redis.StringCmd { return c.next.Get(ctx, key) })
+}
+
+func (c *breakerClient) Set(ctx context.Context, key string, value any, expiration time.Duration) *redis.StatusCmd {
+	return run(ctx, c, redis.NewStatusCmd, func() *redis.StatusCmd { return c.next.Set(ctx, key, value, expiration) })
+}
+
+func (c *breakerClient) SetNX(ctx context.Context, key string, value any, expiration time.Duration) *redis.BoolCmd {
+	return run(ctx, c, redis.NewBoolCmd, func() *redis.BoolCmd { return c.next.SetNX(ctx, key, value, expiration) })
+}
+
+func (c *breakerClient) Del(ctx context.Context, keys ...string) *redis.IntCmd {
+	return run(ctx, c, redis.NewIntCmd, func() *redis.IntCmd { return c.next.Del(ctx, keys...) })
+}
+
+func (c *breakerClient) Incr(ctx context.Context, key string) *redis.IntCmd {
+	return run(ctx, c, redis.NewIntCmd, func() *redis.IntCmd { return c.next.Incr(ctx, key) })
+}
+
+func (c *breakerClient) Publish(ctx context.Context, channel string, message any) *redis.IntCmd {
+	return run(ctx, c, redis.NewIntCmd, func() *redis.IntCmd { return c.next.Publish(ctx, channel, message) })
+}
+
+func (c *breakerClient) Eval(ctx context.Context, script string, keys []string, args ...any) *redis.Cmd {
+	return run(ctx, c, redis.NewCmd, func() *redis.Cmd { return c.next.Eval(ctx, script, keys, args...) })
+}
+
+func (c *breakerClient) EvalSha(ctx context.Context, sha1 string, keys []string, args ...any) *redis.Cmd {
+	return run(ctx, c, redis.NewCmd, func() *redis.Cmd { return c.next.EvalSha(ctx, sha1, keys, args...) })
+}
+
+func (c *breakerClient) EvalRO(ctx context.Context, script string, keys []string, args ...any) *redis.Cmd {
+	return run(ctx, c, redis.NewCmd, func() *redis.Cmd { return c.next.EvalRO(ctx, script, keys, args...) })
+}
+
+func (c *breakerClient) EvalShaRO(ctx context.Context, sha1 string, keys []string, args ...any) *redis.Cmd {
+	return run(ctx, c, redis.NewCmd, func() *redis.Cmd { return c.next.EvalShaRO(ctx, sha1, keys, args...) })
+}
+
+func (c *breakerClient) ScriptExists(ctx context.Context, hashes ...string) *redis.BoolSliceCmd {
+	return run(ctx, c, redis.NewBoolSliceCmd, func() *redis.BoolSliceCmd { return c.next.ScriptExists(ctx, hashes...) })
+}
+
+func (c *breakerClient) ScriptLoad(ctx context.Context, script string) *redis.StringCmd {
+	return run(ctx, c, redis.NewStringCmd, func() *redis.StringCmd { return c.next.ScriptLoad(ctx, script) })
+}
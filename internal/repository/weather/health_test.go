@@ -0,0 +1,98 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/pkg/resilience"
+)
+
+// flakyCache is a fakeCache whose reads and writes fail while down. It counts the reads that
+// reach it.
+type flakyCache struct {
+	*fakeCache
+
+	down  atomic.Bool
+	reads atomic.Int32
+}
+
+func (c *flakyCache) Get(ctx context.Context, key string) *redis.StringCmd {
+	c.reads.Add(1)
+	if c.down.Load() {
+		return redis.NewStringResult("", errors.New("dial tcp 127.0.0.1:6379: connect: connection refused"))
+	}
+
+	return c.fakeCache.Get(ctx, key)
+}
+
+func (c *flakyCache) Set(ctx context.Context, key string, value any, expiration time.Duration) *redis.StatusCmd {
+	if c.down.Load() {
+		return redis.NewStatusResult("", errors.New("dial tcp 127.0.0.1:6379: connect: connection refused"))
+	}
+
+	return c.fakeCache.Set(ctx, key, value, expiration)
+}
+
+func (c *flakyCache) Del(ctx context.Context, keys ...string) *redis.IntCmd {
+	if c.down.Load() {
+		return redis.NewIntResult(0, errors.New("dial tcp 127.0.0.1:6379: connect: connection refused"))
+	}
+
+	return c.fakeCache.Del(ctx, keys...)
+}
+
+func (c *flakyCache) Incr(ctx context.Context, key string) *redis.IntCmd {
+	if c.down.Load() {
+		return redis.NewIntResult(0, errors.New("dial tcp 127.0.0.1:6379: connect: connection refused"))
+	}
+
+	return c.fakeCache.Incr(ctx, key)
+}
+
+func TestCachedWeatherRepo_Breaker(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	london := &domain.Weather{ID: uuid.New(), CityName: "london"}
+	cache := &flakyCache{fakeCache: newFakeCache()}
+	cache.down.Store(true)
+
+	real := &slowRepo{weather: london}
+	breaker := resilience.NewBreaker(resilience.BreakerConfig{FailureThreshold: 2, OpenTimeout: 50 * time.Millisecond})
+	repo := newCachedWeatherRepo(real, cache, time.Hour, WithBreaker(breaker))
+
+	// Act: the first read and write find Redis down, which opens the breaker.
+	for range 4 {
+		got, err := repo.GetByID(ctx, london.ID)
+		require.NoError(t, err)
+		assert.Equal(t, london.ID, got.ID)
+	}
+
+	// Assert
+	assert.EqualValues(t, 1, cache.reads.Load())
+	assert.EqualValues(t, 4, real.calls.Load())
+
+	stats := repo.Stats()
+	assert.True(t, stats.Uncached)
+	assert.NotZero(t, stats.Bypassed)
+
+	// Once Redis is back, a probe after the open timeout brings the cache back.
+	cache.down.Store(false)
+	time.Sleep(60 * time.Millisecond)
+
+	_, err := repo.GetByID(ctx, london.ID)
+	require.NoError(t, err)
+	_, err = repo.GetByID(ctx, london.ID)
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 5, real.calls.Load())
+	assert.Equal(t, resilience.StateClosed, breaker.State())
+	assert.False(t, repo.Stats().Uncached)
+}
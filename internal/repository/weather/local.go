@@ -0,0 +1,146 @@
+package weather
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/xoltawn/weatherhub/internal/domain"
+)
+
+const (
+	// DefaultLocalCacheTTL bounds how long a replica may serve a record another replica changed,
+	// should the invalidation not reach it.
+	DefaultLocalCacheTTL = 30 * time.Second
+	// invalidationChannel carries the IDs of changed records between replicas.
+	invalidationChannel = "weather:invalidate"
+)
+
+// localCache is an in-process LRU of records by ID, bounded in size and in how long records
+// are kept.
+type localCache struct {
+	mu    sync.Mutex
+	size  int
+	ttl   time.Duration
+	items map[uuid.UUID]*list.Element
+	// order holds the *localItem in order of use, most recent first.
+	order *list.List
+}
+
+type localItem struct {
+	value     domain.Weather
+	expiresAt time.Time
+}
+
+func newLocalCache(size int, ttl time.Duration) *localCache {
+	return &localCache{
+		size:  size,
+		ttl:   ttl,
+		items: make(map[uuid.UUID]*list.Element, size),
+		order: list.New(),
+	}
+}
+
+// get returns a deep copy of the record cached under id, unless it has expired.
+func (c *localCache) get(id uuid.UUID, now time.Time) (*domain.Weather, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.items[id]
+	if !ok {
+		return nil, false
+	}
+
+	item := elem.Value.(*localItem)
+	if !now.Before(item.expiresAt) {
+		c.order.Remove(elem)
+		delete(c.items, id)
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+
+	return item.value.Clone(), true
+}
+
+// add caches a deep copy of w, evicting the least recently used record when full.
+func (c *localCache) add(w *domain.Weather, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item := &localItem{value: *w.Clone(), expiresAt: now.Add(c.ttl)}
+	if elem, ok := c.items[w.ID]; ok {
+		elem.Value = item
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[w.ID] = c.order.PushFront(item)
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*localItem).value.ID)
+	}
+}
+
+func (c *localCache) remove(id uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[id]; ok {
+		c.order.Remove(elem)
+		delete(c.items, id)
+	}
+}
+
+// invalidationBus tells the other replicas which records changed, so that they evict their
+// local copies.
+type invalidationBus interface {
+	publish(ctx context.Context, id uuid.UUID) error
+	// subscribe returns the IDs the other replicas publish, until it's closed.
+	subscribe(ctx context.Context) (<-chan uuid.UUID, func())
+}
+
+// redisBus is an invalidationBus over Redis pub/sub. Messages are "<origin>:<id>", origin
+// identifying the publishing replica so that it can skip its own messages. Pub/sub delivers at
+// most once: a replica that misses a message, such as while reconnecting, serves its copy until
+// the copy expires.
+type redisBus struct {
+	rdb *redis.Client
+	// pub publishes, through the proxy's breaker when it has one.
+	pub    cacheClient
+	origin string
+}
+
+func (b *redisBus) publish(ctx context.Context, id uuid.UUID) error {
+	return b.pub.Publish(ctx, invalidationChannel, b.origin+":"+id.String()).Err()
+}
+
+func (b *redisBus) subscribe(ctx context.Context) (<-chan uuid.UUID, func()) {
+	sub := b.rdb.Subscribe(ctx, invalidationChannel)
+	ids := make(chan uuid.UUID)
+
+	go func() {
+		defer close(ids)
+
+		for msg := range sub.Channel() {
+			origin, rawID, _ := strings.Cut(msg.Payload, ":")
+			id, err := uuid.Parse(rawID)
+			if origin == b.origin || err != nil {
+				continue
+			}
+
+			select {
+			case ids <- id:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ids, func() { sub.Close() }
+}
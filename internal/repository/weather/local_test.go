@@ -0,0 +1,226 @@
+package weather
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/internal/repository/mocks"
+)
+
+// memHub connects the memBus of each replica, like a Redis channel.
+type memHub struct {
+	mu   sync.Mutex
+	subs map[string]chan uuid.UUID
+}
+
+func newMemHub() *memHub {
+	return &memHub{subs: make(map[string]chan uuid.UUID)}
+}
+
+func (h *memHub) subscribers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return len(h.subs)
+}
+
+// memBus is an in-memory invalidationBus.
+type memBus struct {
+	hub    *memHub
+	origin string
+}
+
+func (b *memBus) publish(ctx context.Context, id uuid.UUID) error {
+	b.hub.mu.Lock()
+	defer b.hub.mu.Unlock()
+
+	for origin, sub := range b.hub.subs {
+		if origin != b.origin {
+			sub <- id
+		}
+	}
+
+	return nil
+}
+
+func (b *memBus) subscribe(ctx context.Context) (<-chan uuid.UUID, func()) {
+	b.hub.mu.Lock()
+	defer b.hub.mu.Unlock()
+
+	sub := make(chan uuid.UUID, 16)
+	b.hub.subs[b.origin] = sub
+
+	return sub, func() {
+		b.hub.mu.Lock()
+		delete(b.hub.subs, b.origin)
+		b.hub.mu.Unlock()
+	}
+}
+
+func TestLocalCache(t *testing.T) {
+	now := time.Now()
+	london := &domain.Weather{ID: uuid.New(), CityName: "london"}
+	paris := &domain.Weather{ID: uuid.New(), CityName: "paris"}
+	tokyo := &domain.Weather{ID: uuid.New(), CityName: "tokyo"}
+
+	t.Run("evicts-the-least-recently-used", func(t *testing.T) {
+		// Arrange
+		cache := newLocalCache(2, time.Minute)
+		cache.add(london, now)
+		cache.add(paris, now)
+
+		// Act
+		_, _ = cache.get(london.ID, now)
+		cache.add(tokyo, now)
+
+		// Assert
+		_, ok := cache.get(paris.ID, now)
+		assert.False(t, ok)
+
+		got, ok := cache.get(london.ID, now)
+		require.True(t, ok)
+		assert.Equal(t, "london", got.CityName)
+		_, ok = cache.get(tokyo.ID, now)
+		assert.True(t, ok)
+	})
+
+	t.Run("expires-records", func(t *testing.T) {
+		cache := newLocalCache(2, time.Minute)
+		cache.add(london, now)
+
+		_, ok := cache.get(london.ID, now.Add(time.Minute))
+
+		assert.False(t, ok)
+	})
+
+	t.Run("hands-out-copies", func(t *testing.T) {
+		cache := newLocalCache(2, time.Minute)
+		cache.add(london, now)
+
+		got, _ := cache.get(london.ID, now)
+		got.CityName = "londres"
+
+		got, _ = cache.get(london.ID, now)
+		assert.Equal(t, "london", got.CityName)
+	})
+
+	t.Run("copies-city-and-coordinates", func(t *testing.T) {
+		cache := newLocalCache(2, time.Minute)
+		w := &domain.Weather{
+			ID:    uuid.New(),
+			City:  &domain.City{Name: "London", Aliases: []string{"londres"}},
+			Coord: &domain.Coordinates{Lat: 51.5, Lon: -0.13},
+		}
+		cache.add(w, now)
+		w.City.Aliases[0] = "londra"
+
+		got, _ := cache.get(w.ID, now)
+		got.City.Name = "Paris"
+		got.Coord.Lat = 48.9
+
+		got, _ = cache.get(w.ID, now)
+		assert.Equal(t, "London", got.City.Name)
+		assert.Equal(t, []string{"londres"}, got.City.Aliases)
+		assert.Equal(t, 51.5, got.Coord.Lat)
+	})
+}
+
+func TestCachedWeatherRepo_LocalCache(t *testing.T) {
+	ctx := context.Background()
+	london := &domain.Weather{ID: uuid.New(), CityName: "london", Temperature: 12}
+
+	t.Run("serves-from-process-memory", func(t *testing.T) {
+		// Arrange
+		real := &slowRepo{weather: london}
+		repo := newCachedWeatherRepo(real, newFakeCache(), time.Hour, WithLocalCache(10, time.Minute))
+
+		// Act
+		_, err := repo.GetByID(ctx, london.ID)
+		require.NoError(t, err)
+		got, err := repo.GetByID(ctx, london.ID)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, london.Temperature, got.Temperature)
+		assert.EqualValues(t, 1, real.calls.Load())
+		assert.Equal(t, CacheStats{
+			Local: TierStats{Hits: 1, Misses: 1},
+			Redis: TierStats{Hits: 0, Misses: 1},
+		}, repo.Stats())
+	})
+
+	t.Run("falls-back-to-redis", func(t *testing.T) {
+		cache := newFakeCache()
+		cacheWeather(t, cache, london, time.Now().Add(time.Hour), 0)
+		real := &slowRepo{weather: london}
+		repo := newCachedWeatherRepo(real, cache, time.Hour, WithLocalCache(10, time.Minute))
+
+		_, err := repo.GetByID(ctx, london.ID)
+
+		require.NoError(t, err)
+		assert.Zero(t, real.calls.Load())
+		assert.Equal(t, CacheStats{
+			Local: TierStats{Misses: 1},
+			Redis: TierStats{Hits: 1},
+		}, repo.Stats())
+	})
+
+	t.Run("keeps-stale-records-out", func(t *testing.T) {
+		cache := newFakeCache()
+		cacheWeather(t, cache, london, time.Now().Add(-time.Minute), time.Millisecond)
+		real := &slowRepo{weather: &domain.Weather{ID: london.ID, CityName: "london", Temperature: 14}}
+		repo := newCachedWeatherRepo(real, cache, time.Hour, WithHardTTL(2*time.Hour), WithLocalCache(10, time.Minute))
+
+		stale, err := repo.GetByID(ctx, london.ID)
+		require.NoError(t, err)
+		require.Eventually(t, func() bool { return real.calls.Load() == 1 }, time.Second, time.Millisecond)
+		require.Eventually(t, func() bool {
+			entry, ok := get[domain.Weather](ctx, repo, repo.fmtKey(london.ID))
+			return ok && entry.Value.Temperature == 14
+		}, time.Second, time.Millisecond)
+		fresh, err := repo.GetByID(ctx, london.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, 12.0, stale.Temperature)
+		assert.Equal(t, 14.0, fresh.Temperature, "the refreshed record is read rather than the stale copy")
+		assert.Equal(t, TierStats{Misses: 2}, repo.Stats().Local)
+	})
+
+	t.Run("evicts-copies-changed-on-other-replicas", func(t *testing.T) {
+		cache := newFakeCache()
+		hub := newMemHub()
+		updated := &domain.Weather{ID: london.ID, CityName: "london", Temperature: 14}
+
+		real := mocks.NewWeatherRepository(t)
+		real.On("GetByID", mock.Anything, london.ID).Return(london, nil).Once()
+		real.On("Update", mock.Anything, updated).Return(nil).Once()
+
+		a := newCachedWeatherRepo(real, cache, time.Hour, WithLocalCache(10, time.Minute))
+		a.bus = &memBus{hub: hub, origin: "a"}
+		b := newCachedWeatherRepo(real, cache, time.Hour, WithLocalCache(10, time.Minute))
+		b.bus = &memBus{hub: hub, origin: "b"}
+
+		runCtx, stop := context.WithCancel(ctx)
+		defer stop()
+		go b.Run(runCtx)
+		require.Eventually(t, func() bool { return hub.subscribers() == 1 }, time.Second, time.Millisecond)
+
+		_, err := b.GetByID(ctx, london.ID)
+		require.NoError(t, err)
+
+		require.NoError(t, a.Update(ctx, updated))
+
+		// b drops its copy and reads the update from Redis.
+		require.Eventually(t, func() bool {
+			got, err := b.GetByID(ctx, london.ID)
+			return err == nil && got.Temperature == updated.Temperature
+		}, time.Second, time.Millisecond)
+	})
+}
@@ -2,118 +2,709 @@ package weather
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/pkg/errutil"
+	"github.com/xoltawn/weatherhub/pkg/resilience"
+	"golang.org/x/sync/singleflight"
 )
 
-type cachedWeatherRepo struct {
-	realRepo domain.WeatherRepository
-	redis    *redis.Client
-	ttl      time.Duration
+const (
+	// DefaultEarlyRefreshBeta makes records be refreshed shortly before they expire, earlier the
+	// longer they take to load. Higher values refresh earlier.
+	DefaultEarlyRefreshBeta = 1.0
+	// lockPollInterval is how often a replica waiting on another's load checks the cache.
+	lockPollInterval = 20 * time.Millisecond
+	// writeBackTimeout bounds the cache writes that follow a database write. They don't end with
+	// the caller's context, since a caller that gives up once the database write has committed
+	// would otherwise leave the cache behind the database.
+	writeBackTimeout = 2 * time.Second
+)
+
+// cacheClient is the part of the Redis client the proxy uses.
+type cacheClient interface {
+	redis.Scripter
+	Get(ctx context.Context, key string) *redis.StringCmd
+	Set(ctx context.Context, key string, value any, expiration time.Duration) *redis.StatusCmd
+	SetNX(ctx context.Context, key string, value any, expiration time.Duration) *redis.BoolCmd
+	Del(ctx context.Context, keys ...string) *redis.IntCmd
+	Incr(ctx context.Context, key string) *redis.IntCmd
+	Publish(ctx context.Context, channel string, message any) *redis.IntCmd
+}
+
+// unlockScript deletes a lock only if it still holds the caller's token.
+var unlockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// cacheEntry is a cached value along with what refreshes are decided on: when the entry goes
+// stale and how long loading the value took. Stale entries are kept until the hard TTL, to be
+// served while they're refreshed. An entry without a value records that there's none to load.
+type cacheEntry[T any] struct {
+	Value     *T            `json:"v"`
+	ExpiresAt int64         `json:"e"`
+	LoadTime  time.Duration `json:"d"`
+	NotFound  bool          `json:"n,omitempty"`
+}
+
+// CachedWeatherRepo caches records by ID, the latest record of each city and pages of lists in
+// Redis, and optionally records by ID in process too.
+//
+// The latest records and list pages are keyed by a generation that writes bump: the generation
+// of the record's city for latest records, and a single one for lists, which any write may change.
+// A read after a write looks under the new generation and so never finds what was cached before
+// it; entries of past generations are left to expire.
+type CachedWeatherRepo struct {
+	realRepo    domain.WeatherRepository
+	redis       cacheClient
+	ttl         time.Duration
+	hardTTL     time.Duration
+	notFoundTTL time.Duration
+	beta        float64
+	lockTTL     time.Duration
+	codec       Codec
+	breaker     *breakerClient
+	// queue, when set, keeps the invalidations that fail until they're replayed.
+	queue domain.CacheInvalidationRepository
+
+	// local, when set, is consulted before Redis by GetByID. Changes to records are published
+	// on bus so that the other replicas evict their copies.
+	local *localCache
+	bus   invalidationBus
+
+	// loads coalesces the concurrent loads of a key into one database query.
+	loads singleflight.Group
+
+	localHits, localMisses atomic.Uint64
+	redisHits, redisMisses atomic.Uint64
+}
+
+// TierStats counts the lookups of a cache tier.
+type TierStats struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+}
+
+// CacheStats counts the lookups of each cache tier since the repository was created. Only
+// GetByID goes through the local tier.
+type CacheStats struct {
+	Local TierStats `json:"local"`
+	Redis TierStats `json:"redis"`
+	// Uncached is set while Redis is bypassed, and Bypassed counts the Redis commands skipped.
+	Uncached bool   `json:"uncached"`
+	Bypassed uint64 `json:"bypassed"`
+}
+
+type CacheOption func(*CachedWeatherRepo)
+
+// WithEarlyRefresh sets how eagerly records are refreshed before they expire, following the
+// XFetch algorithm: each read refreshes with a probability that grows as expiry nears, scaled by
+// beta and the time the record took to load. Zero disables early refreshes. Defaults to
+// DefaultEarlyRefreshBeta.
+func WithEarlyRefresh(beta float64) CacheOption {
+	return func(r *CachedWeatherRepo) {
+		r.beta = beta
+	}
+}
+
+// WithHardTTL keeps entries for hardTTL, past the TTL the repository was created with. Entries
+// past the TTL are stale: reads still get them right away, and refresh them in the background.
+// Defaults to the TTL, serving nothing stale.
+func WithHardTTL(hardTTL time.Duration) CacheOption {
+	return func(r *CachedWeatherRepo) {
+		r.hardTTL = max(hardTTL, r.ttl)
+	}
+}
+
+// WithNotFoundTTL caches for ttl that a record, or a city's latest record, doesn't exist, so that
+// reads of unknown IDs don't all reach the database. Disabled by default.
+func WithNotFoundTTL(ttl time.Duration) CacheOption {
+	return func(r *CachedWeatherRepo) {
+		r.notFoundTTL = ttl
+	}
 }
 
-func NewCachedWeatherRepo(real domain.WeatherRepository, rdb *redis.Client, ttl time.Duration) domain.WeatherRepository {
-	return &cachedWeatherRepo{
+// WithLock makes replicas sharing the cache take a lock on a key before loading it, so that one
+// of them queries the database while the others wait for the result to be cached. A replica
+// waits at most ttl, which also bounds how long a crashed replica's lock is held. Disabled by
+// default.
+func WithLock(ttl time.Duration) CacheOption {
+	return func(r *CachedWeatherRepo) {
+		r.lockTTL = ttl
+	}
+}
+
+// WithLocalCache keeps up to size records by ID in process, each for at most ttl, in front of
+// Redis. Run must be running for changes on other replicas to evict them. Disabled by default.
+func WithLocalCache(size int, ttl time.Duration) CacheOption {
+	return func(r *CachedWeatherRepo) {
+		if size > 0 && ttl > 0 {
+			r.local = newLocalCache(size, ttl)
+		}
+	}
+}
+
+// WithBreaker makes the proxy stop using Redis while breaker is open, reading through to the
+// database instead of waiting on Redis timeouts. Failed Redis commands count as failures.
+func WithBreaker(breaker *resilience.Breaker) CacheOption {
+	return func(r *CachedWeatherRepo) {
+		r.breaker = &breakerClient{next: r.redis, breaker: breaker}
+		r.redis = r.breaker
+	}
+}
+
+// WithRetryQueue records the cache invalidations that fail in queue, for a worker to replay
+// through Invalidate. Otherwise, what they should have invalidated is served until it expires.
+func WithRetryQueue(queue domain.CacheInvalidationRepository) CacheOption {
+	return func(r *CachedWeatherRepo) {
+		r.queue = queue
+	}
+}
+
+// WithCodec sets how cached values are encoded. Defaults to JSONCodec.
+func WithCodec(codec Codec) CacheOption {
+	return func(r *CachedWeatherRepo) {
+		r.codec = codec
+	}
+}
+
+func NewCachedWeatherRepo(real domain.WeatherRepository, rdb *redis.Client, ttl time.Duration, opts ...CacheOption) *CachedWeatherRepo {
+	r := newCachedWeatherRepo(real, rdb, ttl, opts...)
+	r.bus = &redisBus{rdb: rdb, pub: r.redis, origin: uuid.NewString()}
+
+	return r
+}
+
+func newCachedWeatherRepo(real domain.WeatherRepository, client cacheClient, ttl time.Duration, opts ...CacheOption) *CachedWeatherRepo {
+	r := &CachedWeatherRepo{
 		realRepo: real,
-		redis:    rdb,
+		redis:    client,
 		ttl:      ttl,
+		hardTTL:  ttl,
+		beta:     DefaultEarlyRefreshBeta,
+		codec:    JSONCodec{},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Run evicts the local copies of the records other replicas change, until ctx is done. It
+// returns right away without a local tier.
+func (r *CachedWeatherRepo) Run(ctx context.Context) {
+	if r.local == nil || r.bus == nil {
+		return
+	}
+
+	ids, closeSub := r.bus.subscribe(ctx)
+	defer closeSub()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id, ok := <-ids:
+			if !ok {
+				return
+			}
+			r.local.remove(id)
+		}
 	}
 }
 
-func (r *cachedWeatherRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Weather, error) {
-	cacheKey := r.fmtKey(id)
+// Stats returns the hits and misses of each tier.
+func (r *CachedWeatherRepo) Stats() CacheStats {
+	stats := CacheStats{
+		Local: TierStats{Hits: r.localHits.Load(), Misses: r.localMisses.Load()},
+		Redis: TierStats{Hits: r.redisHits.Load(), Misses: r.redisMisses.Load()},
+	}
+	if r.breaker != nil {
+		stats.Uncached = r.breaker.breaker.State() != resilience.StateClosed
+		stats.Bypassed = r.breaker.bypassed.Load()
+	}
+
+	return stats
+}
 
-	val, err := r.redis.Get(ctx, cacheKey).Result()
-	if err == nil {
-		var weather domain.Weather
-		if json.Unmarshal([]byte(val), &weather) == nil {
-			return &weather, nil
+func (r *CachedWeatherRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Weather, error) {
+	if r.local != nil {
+		if w, ok := r.local.get(id, time.Now()); ok {
+			r.localHits.Add(1)
+			return w, nil
 		}
+		r.localMisses.Add(1)
 	}
 
-	weather, err := r.realRepo.GetByID(ctx, id)
+	w, stale, err := lookup(ctx, r, r.fmtKey(id), func(ctx context.Context) (*domain.Weather, error) {
+		return r.realRepo.GetByID(ctx, id)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	data, marshalErr := json.Marshal(weather)
-	if marshalErr != nil {
-		//log it or put in job , there is no need to affect user response for this (not sensitive data)
-		return weather, nil
+	// A stale record stays out of process memory, where it would outlive the refresh.
+	if r.local != nil && !stale {
+		r.local.add(w, time.Now())
 	}
 
-	setCmd := r.redis.Set(ctx, cacheKey, data, r.ttl)
-	if setCmd.Err() != nil {
-		//JUST log , there is no need to affect user response for this
+	return w, nil
+}
+
+func (r *CachedWeatherRepo) GetLatestByCity(ctx context.Context, cityID uuid.UUID) (*domain.Weather, error) {
+	gen, ok := r.generation(ctx, r.cityGenKey(cityID))
+	if !ok {
+		return r.realRepo.GetLatestByCity(ctx, cityID)
 	}
 
-	return weather, nil
+	key := fmt.Sprintf("weather:city:%s:latest:%s", cityID, gen)
+
+	return cached(ctx, r, key, func(ctx context.Context) (*domain.Weather, error) {
+		return r.realRepo.GetLatestByCity(ctx, cityID)
+	})
 }
 
-func (r *cachedWeatherRepo) Create(ctx context.Context, w *domain.Weather) error {
-	if err := r.realRepo.Create(ctx, w); err != nil {
-		return err
+func (r *CachedWeatherRepo) List(ctx context.Context, query domain.WeatherQuery) (*domain.WeatherPage, error) {
+	gen, ok := r.generation(ctx, listGenKey)
+	if !ok {
+		return r.realRepo.List(ctx, query)
+	}
+
+	data, err := json.Marshal(query)
+	if err != nil {
+		return r.realRepo.List(ctx, query)
+	}
+	sum := sha256.Sum256(data)
+	key := fmt.Sprintf("weather:list:%s:%s", gen, hex.EncodeToString(sum[:]))
+
+	return cached(ctx, r, key, func(ctx context.Context) (*domain.WeatherPage, error) {
+		return r.realRepo.List(ctx, query)
+	})
+}
+
+// listGenKey holds the generation of the cached list pages.
+const listGenKey = "weather:list:gen"
+
+func (r *CachedWeatherRepo) cityGenKey(cityID uuid.UUID) string {
+	return fmt.Sprintf("weather:city:%s:gen", cityID)
+}
+
+// generation returns the generation under key, "0" before the first bump. It fails when Redis
+// can't be read, in which case the cache is bypassed rather than risking a stale generation.
+func (r *CachedWeatherRepo) generation(ctx context.Context, key string) (string, bool) {
+	gen, err := r.redis.Get(ctx, key).Result()
+	switch {
+	case errors.Is(err, redis.Nil):
+		return "0", true
+	case err != nil:
+		return "", false
+	}
+
+	return gen, true
+}
+
+// invalidate bumps the generations a write to a record of the city changes: the city's latest
+// record, when the record has a city, and the lists.
+func (r *CachedWeatherRepo) invalidate(ctx context.Context, cityID *uuid.UUID) {
+	if cityID != nil {
+		key := r.cityGenKey(*cityID)
+		if err := r.redis.Incr(ctx, key).Err(); err != nil {
+			r.enqueue(ctx, domain.CacheOpIncr, key, err)
+		}
+	}
+
+	if err := r.redis.Incr(ctx, listGenKey).Err(); err != nil {
+		r.enqueue(ctx, domain.CacheOpIncr, listGenKey, err)
+	}
+}
+
+// enqueue records an invalidation that failed with err, to be replayed later.
+func (r *CachedWeatherRepo) enqueue(ctx context.Context, op domain.CacheOp, key string, err error) {
+	if r.queue == nil {
+		return
+	}
+
+	inv := &domain.CacheInvalidation{
+		ID:            uuid.New(),
+		Op:            op,
+		Key:           key,
+		NextAttemptAt: time.Now(),
+		LastError:     err.Error(),
+	}
+
+	// The failed cache write may have used up its context, by timing out for one.
+	ctx, cancel := writeBackContext(ctx)
+	defer cancel()
+
+	if err := r.queue.Create(ctx, inv); err != nil {
+		log.Printf("weather cache: failed to queue %s %s: %v", op, key, err)
 	}
+}
 
-	data, marshalErr := json.Marshal(w)
-	if marshalErr != nil {
-		//log it or put in job , there is no need to affect user response for this (not sensitive data)
+// Invalidate applies an invalidation that failed before. Deleting a record's key also evicts
+// the copies of the record in process memory, which may have been read from Redis meanwhile.
+func (r *CachedWeatherRepo) Invalidate(ctx context.Context, inv *domain.CacheInvalidation) error {
+	switch inv.Op {
+	case domain.CacheOpDelete:
+		if err := r.redis.Del(ctx, inv.Key).Err(); err != nil {
+			return err
+		}
+		if id, ok := r.parseKey(inv.Key); ok {
+			if r.local != nil {
+				r.local.remove(id)
+			}
+			r.publish(ctx, id)
+		}
 		return nil
+	case domain.CacheOpIncr:
+		return r.redis.Incr(ctx, inv.Key).Err()
+	default:
+		return errutil.Wrapf(domain.ErrInvalidInput, "unknown cache operation %q", inv.Op)
+	}
+}
+
+// cached returns the value cached under key, loading it on a miss and, now and then, shortly
+// before it goes stale. A stale value is returned as is and refreshed in the background.
+func cached[T any](ctx context.Context, r *CachedWeatherRepo, key string, load func(context.Context) (*T, error)) (*T, error) {
+	value, _, err := lookup(ctx, r, key, load)
+	return value, err
+}
+
+// lookup is cached, also reporting whether the value returned is stale.
+func lookup[T any](ctx context.Context, r *CachedWeatherRepo, key string, load func(context.Context) (*T, error)) (*T, bool, error) {
+	entry, ok := get[T](ctx, r, key)
+	if !ok {
+		r.redisMisses.Add(1)
+		value, err := loadShared(ctx, r, key, load)
+		return value, false, err
 	}
+	r.redisHits.Add(1)
 
-	setCmd := r.redis.Set(ctx, "weather:"+w.ID.String(), data, r.ttl)
-	if setCmd.Err() != nil {
-		//JUST log , there is no need to affect user response for this
+	now := time.Now()
+	switch {
+	case entry.NotFound:
+		return nil, false, errCachedNotFound
+	case r.hardTTL > r.ttl && !now.Before(time.UnixMilli(entry.ExpiresAt)):
+		refresh(ctx, r, key, load)
+		return entry.Value, true, nil
+	case r.refreshEarly(entry.ExpiresAt, entry.LoadTime, now):
+		value, err := loadShared(ctx, r, key, load)
+		if err != nil {
+			// The early refresh failed, but the cached value isn't stale yet.
+			return entry.Value, false, nil
+		}
+		return value, false, nil
+	}
+
+	return entry.Value, false, nil
+}
+
+var errCachedNotFound = errutil.Wrap(domain.ErrNotFound, "repository.CachedWeather")
+
+// result is what a read of the entry returns.
+func (e *cacheEntry[T]) result() (*T, error) {
+	if e.NotFound {
+		return nil, errCachedNotFound
+	}
+
+	return e.Value, nil
+}
+
+// get returns the entry cached under key, if there's one that can be decoded. Entries written
+// in another format are left to be overwritten.
+func get[T any](ctx context.Context, r *CachedWeatherRepo, key string) (*cacheEntry[T], bool) {
+	val, err := r.redis.Get(ctx, key).Bytes()
+	if err != nil {
+		return nil, false
+	}
+
+	var entry cacheEntry[T]
+	if decode(r.codec, val, &entry) != nil || (entry.Value == nil && !entry.NotFound) {
+		return nil, false
+	}
+
+	return &entry, true
+}
+
+// refreshEarly decides whether a read refreshes an entry that hasn't expired yet.
+func (r *CachedWeatherRepo) refreshEarly(expiresAt int64, loadTime time.Duration, now time.Time) bool {
+	if r.beta <= 0 || loadTime <= 0 {
+		return false
+	}
+
+	// -ln(U) for U uniform in (0, 1] is exponentially distributed, so refreshes cluster right
+	// before expiry but reads a little earlier get a chance too.
+	gap := time.Duration(float64(loadTime) * r.beta * -math.Log(1-rand.Float64()))
+
+	return !now.Add(gap).Before(time.UnixMilli(expiresAt))
+}
+
+// refresh reloads a value in the background, unless it's being loaded already.
+func refresh[T any](ctx context.Context, r *CachedWeatherRepo, key string, load func(context.Context) (*T, error)) {
+	r.loads.DoChan(key, func() (any, error) {
+		return fill(context.WithoutCancel(ctx), r, key, load)
+	})
+}
+
+// loadShared loads a value and caches it, sharing the result with the callers loading the same
+// key meanwhile. The load isn't cancelled with the caller that started it, since others may be
+// waiting on it.
+func loadShared[T any](ctx context.Context, r *CachedWeatherRepo, key string, load func(context.Context) (*T, error)) (*T, error) {
+	result := r.loads.DoChan(key, func() (any, error) {
+		return fill(context.WithoutCancel(ctx), r, key, load)
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-result:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		// Each caller gets its own deep copy, since callers may modify it.
+		return clone(res.Val.(*T)), nil
+	}
+}
+
+// clone returns a deep copy of a cached value, so that callers sharing a load share neither the
+// value nor its city, coordinates or page items.
+func clone[T any](v *T) *T {
+	switch v := any(v).(type) {
+	case *domain.Weather:
+		return any(v.Clone()).(*T)
+	case *domain.WeatherPage:
+		return any(v.Clone()).(*T)
+	}
+
+	value := *v
+	return &value
+}
+
+// fill loads a value and caches it. With a lock, a replica that finds the key locked waits for
+// the holder to cache the value instead, and loads it itself only if that takes longer than the
+// lock lasts.
+func fill[T any](ctx context.Context, r *CachedWeatherRepo, key string, load func(context.Context) (*T, error)) (*T, error) {
+	if r.lockTTL > 0 {
+		lockKey := key + ":lock"
+		token := uuid.NewString()
+
+		acquired, err := r.redis.SetNX(ctx, lockKey, token, r.lockTTL).Result()
+		switch {
+		case err != nil:
+			// Without a working lock, load the value like a single replica would.
+		case acquired:
+			defer unlockScript.Run(ctx, r.redis, []string{lockKey}, token)
+		default:
+			if entry, ok := await[T](ctx, r, key, time.Now().Add(r.lockTTL)); ok {
+				return entry.result()
+			}
+		}
+	}
+
+	start := time.Now()
+	value, err := load(ctx)
+	if errors.Is(err, domain.ErrNotFound) && r.notFoundTTL > 0 {
+		if err := setNotFound[T](ctx, r, key); err != nil {
+			logFailure("cache not found", key, err)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Failing to cache a loaded value only costs another load, so it isn't retried.
+	if err := set(ctx, r, key, value, time.Since(start)); err != nil {
+		logFailure("cache", key, err)
+	}
+
+	return value, nil
+}
+
+// await polls the cache for key until deadline.
+func await[T any](ctx context.Context, r *CachedWeatherRepo, key string, deadline time.Time) (*cacheEntry[T], bool) {
+	ticker := time.NewTicker(lockPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if entry, ok := get[T](ctx, r, key); ok {
+			return entry, true
+		}
+		if !time.Now().Add(lockPollInterval).Before(deadline) {
+			return nil, false
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case <-ticker.C:
+		}
+	}
+}
+
+// set caches a value along with how long it took to load, zero when it wasn't loaded.
+func set[T any](ctx context.Context, r *CachedWeatherRepo, key string, value *T, loadTime time.Duration) error {
+	return store(ctx, r, key, &cacheEntry[T]{
+		Value:     value,
+		ExpiresAt: time.Now().Add(r.ttl).UnixMilli(),
+		LoadTime:  loadTime,
+	}, r.hardTTL)
+}
+
+// setNotFound caches that there's no value to load under key.
+func setNotFound[T any](ctx context.Context, r *CachedWeatherRepo, key string) error {
+	return store(ctx, r, key, &cacheEntry[T]{
+		NotFound:  true,
+		ExpiresAt: time.Now().Add(r.notFoundTTL).UnixMilli(),
+	}, r.notFoundTTL)
+}
+
+func store[T any](ctx context.Context, r *CachedWeatherRepo, key string, entry *cacheEntry[T], ttl time.Duration) error {
+	data, err := encode(r.codec, entry)
+	if err != nil {
+		return err
+	}
+
+	return r.redis.Set(ctx, key, data, ttl).Err()
+}
+
+func (r *CachedWeatherRepo) Create(ctx context.Context, w *domain.Weather) error {
+	if err := r.realRepo.Create(ctx, w); err != nil {
+		return err
+	}
+
+	ctx, cancel := writeBackContext(ctx)
+	defer cancel()
+
+	r.setRecord(ctx, w)
+	r.invalidate(ctx, w.CityID)
+	if r.local != nil {
+		r.local.add(w, time.Now())
 	}
 
 	return nil
 }
 
-func (r *cachedWeatherRepo) fmtKey(id uuid.UUID) string {
+// writeBackContext returns the context of the cache writes that follow a database write.
+func writeBackContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), writeBackTimeout)
+}
+
+// setRecord caches a record just written. Should that fail, the key is deleted later instead,
+// since what's cached under it, if anything, is outdated.
+func (r *CachedWeatherRepo) setRecord(ctx context.Context, w *domain.Weather) {
+	if err := set(ctx, r, r.fmtKey(w.ID), w, 0); err != nil {
+		r.enqueue(ctx, domain.CacheOpDelete, r.fmtKey(w.ID), err)
+	}
+}
+
+func (r *CachedWeatherRepo) fmtKey(id uuid.UUID) string {
 	return fmt.Sprintf("weather:%s", id.String())
 }
 
-func (r *cachedWeatherRepo) Update(ctx context.Context, w *domain.Weather) error {
+// parseKey returns the ID of the record cached under key, if key is a record's.
+func (r *CachedWeatherRepo) parseKey(key string) (uuid.UUID, bool) {
+	rawID, ok := strings.CutPrefix(key, "weather:")
+	if !ok {
+		return uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(rawID)
+	return id, err == nil
+}
+
+func (r *CachedWeatherRepo) Update(ctx context.Context, w *domain.Weather) error {
 	if err := r.realRepo.Update(ctx, w); err != nil {
 		return err
 	}
 
-	data, marshalErr := json.Marshal(w)
-	if marshalErr != nil {
-		//log it or put in job , there is no need to affect user response for this (not sensitive data)
-		return nil
-	}
+	ctx, cancel := writeBackContext(ctx)
+	defer cancel()
 
-	setCmd := r.redis.Set(ctx, r.fmtKey(w.ID), data, r.ttl)
-	if setCmd.Err() != nil {
-		//log it or put in job , there is no need to affect user response for this (not sensitive data)
+	r.setRecord(ctx, w)
+	r.invalidate(ctx, w.CityID)
+	if r.local != nil {
+		r.local.add(w, time.Now())
 	}
+	r.publish(ctx, w.ID)
 
 	return nil
 }
 
-func (r *cachedWeatherRepo) Delete(ctx context.Context, id uuid.UUID) error {
+func (r *CachedWeatherRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	// The record's city is needed to invalidate the city's latest record.
+	existing, err := r.realRepo.GetByID(ctx, id)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return err
+	}
+
 	if err := r.realRepo.Delete(ctx, id); err != nil {
 		return err
 	}
 
-	delCmd := r.redis.Del(ctx, r.fmtKey(id))
-	if delCmd.Err() != nil {
-		//log it or put in job , there is no need to affect user response for this (not sensitive data)
+	ctx, cancel := writeBackContext(ctx)
+	defer cancel()
+
+	if err := r.redis.Del(ctx, r.fmtKey(id)).Err(); err != nil {
+		// Until it's replayed, the deleted record is served from the cache.
+		r.enqueue(ctx, domain.CacheOpDelete, r.fmtKey(id), err)
+	}
+	if r.local != nil {
+		r.local.remove(id)
 	}
+	r.publish(ctx, id)
+
+	var cityID *uuid.UUID
+	if existing != nil {
+		cityID = existing.CityID
+	}
+	r.invalidate(ctx, cityID)
 
 	return nil
 }
 
-func (r *cachedWeatherRepo) GetAll(ctx context.Context) ([]domain.Weather, error) {
-	return r.realRepo.GetAll(ctx)
+// publish tells the other replicas to evict their local copies of the record.
+func (r *CachedWeatherRepo) publish(ctx context.Context, id uuid.UUID) {
+	if r.bus == nil {
+		return
+	}
+
+	// Other replicas that miss the eviction serve their copies until they expire.
+	if err := r.bus.publish(ctx, id); err != nil {
+		logFailure("publish eviction of", r.fmtKey(id), err)
+	}
+}
+
+// logFailure logs a cache write that failed and isn't retried. Writes skipped while the breaker
+// is open aren't logged, since Stats counts them.
+func logFailure(action, key string, err error) {
+	if errors.Is(err, resilience.ErrCircuitOpen) {
+		return
+	}
+
+	log.Printf("weather cache: failed to %s %s: %v", action, key, err)
+}
+
+func (r *CachedWeatherRepo) GetHistory(ctx context.Context, query domain.WeatherHistoryQuery, limit int) ([]domain.Weather, error) {
+	return r.realRepo.GetHistory(ctx, query, limit)
 }
 
-func (r *cachedWeatherRepo) GetLatestByCity(ctx context.Context, cityName string) (*domain.Weather, error) {
-	return r.realRepo.GetLatestByCity(ctx, cityName)
+func (r *CachedWeatherRepo) GetHistoryBuckets(ctx context.Context, query domain.WeatherHistoryQuery) ([]domain.WeatherBucket, error) {
+	return r.realRepo.GetHistoryBuckets(ctx, query)
 }
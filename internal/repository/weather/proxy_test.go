@@ -0,0 +1,634 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/internal/repository/mocks"
+)
+
+// fakeCache is an in-memory cacheClient without expiry. Its only script is unlockScript.
+type fakeCache struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func newFakeCache() *fakeCache {
+	return &fakeCache{data: make(map[string]string)}
+}
+
+func (c *fakeCache) Get(ctx context.Context, key string) *redis.StringCmd {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	val, ok := c.data[key]
+	if !ok {
+		return redis.NewStringResult("", redis.Nil)
+	}
+
+	return redis.NewStringResult(val, nil)
+}
+
+func (c *fakeCache) Set(ctx context.Context, key string, value any, expiration time.Duration) *redis.StatusCmd {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.data[key] = toString(value)
+
+	return redis.NewStatusResult("OK", nil)
+}
+
+func (c *fakeCache) SetNX(ctx context.Context, key string, value any, expiration time.Duration) *redis.BoolCmd {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.data[key]; ok {
+		return redis.NewBoolResult(false, nil)
+	}
+	c.data[key] = toString(value)
+
+	return redis.NewBoolResult(true, nil)
+}
+
+func (c *fakeCache) Del(ctx context.Context, keys ...string) *redis.IntCmd {
+	if err := ctx.Err(); err != nil {
+		return redis.NewIntResult(0, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var n int64
+	for _, key := range keys {
+		if _, ok := c.data[key]; ok {
+			delete(c.data, key)
+			n++
+		}
+	}
+
+	return redis.NewIntResult(n, nil)
+}
+
+func (c *fakeCache) Incr(ctx context.Context, key string) *redis.IntCmd {
+	if err := ctx.Err(); err != nil {
+		return redis.NewIntResult(0, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n, _ := strconv.ParseInt(c.data[key], 10, 64)
+	n++
+	c.data[key] = strconv.FormatInt(n, 10)
+
+	return redis.NewIntResult(n, nil)
+}
+
+func (c *fakeCache) Publish(ctx context.Context, channel string, message any) *redis.IntCmd {
+	return redis.NewIntResult(0, nil)
+}
+
+func (c *fakeCache) Eval(ctx context.Context, script string, keys []string, args ...any) *redis.Cmd {
+	return c.EvalSha(ctx, "", keys, args...)
+}
+
+func (c *fakeCache) EvalSha(ctx context.Context, sha1 string, keys []string, args ...any) *redis.Cmd {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.data[keys[0]] != toString(args[0]) {
+		return redis.NewCmdResult(int64(0), nil)
+	}
+	delete(c.data, keys[0])
+
+	return redis.NewCmdResult(int64(1), nil)
+}
+
+func (c *fakeCache) EvalRO(ctx context.Context, script string, keys []string, args ...any) *redis.Cmd {
+	return c.Eval(ctx, script, keys, args...)
+}
+
+func (c *fakeCache) EvalShaRO(ctx context.Context, sha1 string, keys []string, args ...any) *redis.Cmd {
+	return c.EvalSha(ctx, sha1, keys, args...)
+}
+
+func (c *fakeCache) ScriptExists(ctx context.Context, hashes ...string) *redis.BoolSliceCmd {
+	return redis.NewBoolSliceResult(make([]bool, len(hashes)), nil)
+}
+
+func (c *fakeCache) ScriptLoad(ctx context.Context, script string) *redis.StringCmd {
+	return redis.NewStringResult("", nil)
+}
+
+func (c *fakeCache) has(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.data[key]
+	return ok
+}
+
+func toString(value any) string {
+	if b, ok := value.([]byte); ok {
+		return string(b)
+	}
+	return value.(string)
+}
+
+// slowRepo is a WeatherRepository whose GetByID takes delay and counts its calls.
+type slowRepo struct {
+	domain.WeatherRepository
+
+	weather *domain.Weather
+	err     error
+	delay   time.Duration
+	calls   atomic.Int32
+}
+
+func (r *slowRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Weather, error) {
+	r.calls.Add(1)
+	time.Sleep(r.delay)
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	weather := *r.weather
+	return &weather, nil
+}
+
+func cacheWeather(t *testing.T, cache *fakeCache, w *domain.Weather, expiresAt time.Time, loadTime time.Duration) {
+	data, err := encode(JSONCodec{}, &cacheEntry[domain.Weather]{Value: w, ExpiresAt: expiresAt.UnixMilli(), LoadTime: loadTime})
+	require.NoError(t, err)
+	cache.Set(context.Background(), "weather:"+w.ID.String(), data, 0)
+}
+
+func TestCachedWeatherRepo_GetByID(t *testing.T) {
+	ctx := context.Background()
+	london := &domain.Weather{ID: uuid.New(), CityName: "london", Temperature: 12}
+
+	t.Run("coalesces-concurrent-misses", func(t *testing.T) {
+		// Arrange
+		cache := newFakeCache()
+		real := &slowRepo{weather: london, delay: 50 * time.Millisecond}
+		repo := newCachedWeatherRepo(real, cache, time.Hour)
+
+		// Act
+		const callers = 10
+		var wg sync.WaitGroup
+		results := make([]*domain.Weather, callers)
+		for i := range callers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i], _ = repo.GetByID(ctx, london.ID)
+			}()
+		}
+		wg.Wait()
+
+		// Assert
+		assert.EqualValues(t, 1, real.calls.Load())
+		for _, w := range results {
+			require.NotNil(t, w)
+			assert.Equal(t, london.ID, w.ID)
+		}
+		assert.NotSame(t, results[0], results[1])
+
+		entry, ok := get[domain.Weather](ctx, repo, repo.fmtKey(london.ID))
+		require.True(t, ok)
+		assert.GreaterOrEqual(t, entry.LoadTime, 50*time.Millisecond)
+	})
+
+	t.Run("coalesced-callers-get-deep-copies", func(t *testing.T) {
+		withCity := &domain.Weather{
+			ID:    uuid.New(),
+			City:  &domain.City{Name: "London", Aliases: []string{"londres"}},
+			Coord: &domain.Coordinates{Lat: 51.5, Lon: -0.13},
+		}
+		real := &slowRepo{weather: withCity, delay: 50 * time.Millisecond}
+		repo := newCachedWeatherRepo(real, newFakeCache(), time.Hour)
+
+		var wg sync.WaitGroup
+		results := make([]*domain.Weather, 2)
+		for i := range results {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i], _ = repo.GetByID(ctx, withCity.ID)
+			}()
+		}
+		wg.Wait()
+
+		require.EqualValues(t, 1, real.calls.Load())
+		require.NotNil(t, results[0])
+		require.NotNil(t, results[1])
+		assert.NotSame(t, results[0].City, results[1].City)
+		assert.NotSame(t, results[0].Coord, results[1].Coord)
+		results[0].City.Aliases[0] = "londra"
+		assert.Equal(t, []string{"londres"}, results[1].City.Aliases)
+	})
+
+	t.Run("serves-cached-record", func(t *testing.T) {
+		cache := newFakeCache()
+		real := &slowRepo{weather: london}
+		repo := newCachedWeatherRepo(real, cache, time.Hour)
+		cacheWeather(t, cache, london, time.Now().Add(time.Hour), time.Millisecond)
+
+		w, err := repo.GetByID(ctx, london.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, 12.0, w.Temperature)
+		assert.Zero(t, real.calls.Load())
+	})
+
+	t.Run("refreshes-early", func(t *testing.T) {
+		cache := newFakeCache()
+		real := &slowRepo{weather: &domain.Weather{ID: london.ID, Temperature: 14}}
+		repo := newCachedWeatherRepo(real, cache, time.Hour)
+		cacheWeather(t, cache, london, time.Now(), time.Second)
+
+		w, err := repo.GetByID(ctx, london.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, 14.0, w.Temperature)
+		assert.EqualValues(t, 1, real.calls.Load())
+	})
+
+	t.Run("failed-early-refresh-serves-cached-record", func(t *testing.T) {
+		cache := newFakeCache()
+		real := &slowRepo{err: errors.New("connection reset")}
+		repo := newCachedWeatherRepo(real, cache, time.Hour)
+		cacheWeather(t, cache, london, time.Now(), time.Second)
+
+		w, err := repo.GetByID(ctx, london.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, 12.0, w.Temperature)
+	})
+
+	t.Run("waits-for-lock-holder", func(t *testing.T) {
+		cache := newFakeCache()
+		real := &slowRepo{weather: london}
+		repo := newCachedWeatherRepo(real, cache, time.Hour, WithLock(time.Second))
+
+		key := repo.fmtKey(london.ID)
+		cache.SetNX(ctx, key+":lock", "another-replica", time.Second)
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			cacheWeather(t, cache, london, time.Now().Add(time.Hour), time.Millisecond)
+		}()
+
+		w, err := repo.GetByID(ctx, london.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, london.ID, w.ID)
+		assert.Zero(t, real.calls.Load())
+	})
+
+	t.Run("loads-when-lock-holder-is-gone", func(t *testing.T) {
+		cache := newFakeCache()
+		real := &slowRepo{weather: london}
+		repo := newCachedWeatherRepo(real, cache, time.Hour, WithLock(60*time.Millisecond))
+
+		key := repo.fmtKey(london.ID)
+		cache.SetNX(ctx, key+":lock", "crashed-replica", time.Second)
+
+		w, err := repo.GetByID(ctx, london.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, london.ID, w.ID)
+		assert.EqualValues(t, 1, real.calls.Load())
+	})
+
+	t.Run("releases-lock", func(t *testing.T) {
+		cache := newFakeCache()
+		real := &slowRepo{weather: london}
+		repo := newCachedWeatherRepo(real, cache, time.Hour, WithLock(time.Second))
+
+		_, err := repo.GetByID(ctx, london.ID)
+
+		require.NoError(t, err)
+		assert.False(t, cache.has(repo.fmtKey(london.ID)+":lock"))
+		assert.True(t, cache.has(repo.fmtKey(london.ID)))
+	})
+
+	t.Run("serves-stale-record-while-refreshing", func(t *testing.T) {
+		cache := newFakeCache()
+		real := &slowRepo{weather: &domain.Weather{ID: london.ID, Temperature: 14}, delay: 20 * time.Millisecond}
+		repo := newCachedWeatherRepo(real, cache, time.Hour, WithHardTTL(2*time.Hour))
+		cacheWeather(t, cache, london, time.Now().Add(-time.Minute), time.Millisecond)
+
+		w, err := repo.GetByID(ctx, london.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, 12.0, w.Temperature)
+		require.Eventually(t, func() bool {
+			entry, ok := get[domain.Weather](ctx, repo, repo.fmtKey(london.ID))
+			return ok && entry.Value.Temperature == 14
+		}, time.Second, 5*time.Millisecond)
+		assert.EqualValues(t, 1, real.calls.Load())
+	})
+
+	t.Run("caches-not-found", func(t *testing.T) {
+		cache := newFakeCache()
+		real := &slowRepo{err: domain.ErrNotFound}
+		repo := newCachedWeatherRepo(real, cache, time.Hour, WithNotFoundTTL(time.Minute))
+
+		_, err := repo.GetByID(ctx, london.ID)
+		require.ErrorIs(t, err, domain.ErrNotFound)
+		_, err = repo.GetByID(ctx, london.ID)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.EqualValues(t, 1, real.calls.Load())
+	})
+
+	t.Run("create-replaces-not-found", func(t *testing.T) {
+		cache := newFakeCache()
+		real := mocks.NewWeatherRepository(t)
+		real.On("GetByID", mock.Anything, london.ID).Return(nil, domain.ErrNotFound).Once()
+		real.On("Create", mock.Anything, london).Return(nil).Once()
+		repo := newCachedWeatherRepo(real, cache, time.Hour, WithNotFoundTTL(time.Minute))
+
+		_, err := repo.GetByID(ctx, london.ID)
+		require.ErrorIs(t, err, domain.ErrNotFound)
+		require.NoError(t, repo.Create(ctx, london))
+
+		w, err := repo.GetByID(ctx, london.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, london.ID, w.ID)
+	})
+
+	t.Run("not-found-is-not-cached-by-default", func(t *testing.T) {
+		cache := newFakeCache()
+		real := &slowRepo{err: domain.ErrNotFound}
+		repo := newCachedWeatherRepo(real, cache, time.Hour)
+
+		_, _ = repo.GetByID(ctx, london.ID)
+		_, err := repo.GetByID(ctx, london.ID)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.EqualValues(t, 2, real.calls.Load())
+	})
+}
+
+func TestCachedWeatherRepo_RefreshEarly(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		beta      float64
+		expiresAt time.Time
+		loadTime  time.Duration
+		want      bool
+	}{
+		{name: "far-from-expiry", beta: 1, expiresAt: now.Add(time.Hour), loadTime: time.Millisecond, want: false},
+		{name: "at-expiry", beta: 1, expiresAt: now, loadTime: time.Millisecond, want: true},
+		{name: "written-not-loaded", beta: 1, expiresAt: now, want: false},
+		{name: "disabled", beta: 0, expiresAt: now, loadTime: time.Second, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newCachedWeatherRepo(nil, newFakeCache(), time.Hour, WithEarlyRefresh(tt.beta))
+
+			assert.Equal(t, tt.want, repo.refreshEarly(tt.expiresAt.UnixMilli(), tt.loadTime, now))
+		})
+	}
+}
+
+func TestCachedWeatherRepo_GetLatestByCity(t *testing.T) {
+	ctx := context.Background()
+	londonID, parisID := uuid.New(), uuid.New()
+	older := &domain.Weather{ID: uuid.New(), CityID: &londonID, Temperature: 10}
+	newer := &domain.Weather{ID: uuid.New(), CityID: &londonID, Temperature: 11}
+
+	t.Run("caches-latest-record", func(t *testing.T) {
+		// Arrange
+		real := mocks.NewWeatherRepository(t)
+		repo := newCachedWeatherRepo(real, newFakeCache(), time.Hour)
+		real.On("GetLatestByCity", mock.Anything, londonID).Return(older, nil).Once()
+
+		// Act
+		first, err := repo.GetLatestByCity(ctx, londonID)
+		require.NoError(t, err)
+		second, err := repo.GetLatestByCity(ctx, londonID)
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, older.ID, first.ID)
+		assert.Equal(t, older.ID, second.ID)
+	})
+
+	t.Run("create-invalidates-city", func(t *testing.T) {
+		real := mocks.NewWeatherRepository(t)
+		repo := newCachedWeatherRepo(real, newFakeCache(), time.Hour)
+		real.On("GetLatestByCity", mock.Anything, londonID).Return(older, nil).Once()
+		real.On("GetLatestByCity", mock.Anything, parisID).Return(&domain.Weather{CityID: &parisID}, nil).Once()
+		real.On("Create", mock.Anything, newer).Return(nil)
+		real.On("GetLatestByCity", mock.Anything, londonID).Return(newer, nil).Once()
+
+		_, err := repo.GetLatestByCity(ctx, londonID)
+		require.NoError(t, err)
+		_, err = repo.GetLatestByCity(ctx, parisID)
+		require.NoError(t, err)
+		require.NoError(t, repo.Create(ctx, newer))
+
+		latest, err := repo.GetLatestByCity(ctx, londonID)
+		require.NoError(t, err)
+		assert.Equal(t, newer.ID, latest.ID)
+
+		// Paris is still cached.
+		_, err = repo.GetLatestByCity(ctx, parisID)
+		require.NoError(t, err)
+	})
+
+	t.Run("delete-invalidates-city", func(t *testing.T) {
+		real := mocks.NewWeatherRepository(t)
+		repo := newCachedWeatherRepo(real, newFakeCache(), time.Hour)
+		real.On("GetLatestByCity", mock.Anything, londonID).Return(newer, nil).Once()
+		real.On("GetByID", mock.Anything, newer.ID).Return(newer, nil)
+		real.On("Delete", mock.Anything, newer.ID).Return(nil)
+		real.On("GetLatestByCity", mock.Anything, londonID).Return(older, nil).Once()
+
+		_, err := repo.GetLatestByCity(ctx, londonID)
+		require.NoError(t, err)
+		require.NoError(t, repo.Delete(ctx, newer.ID))
+
+		latest, err := repo.GetLatestByCity(ctx, londonID)
+		require.NoError(t, err)
+		assert.Equal(t, older.ID, latest.ID)
+	})
+}
+
+func TestCachedWeatherRepo_List(t *testing.T) {
+	ctx := context.Background()
+	cityID := uuid.New()
+	london := domain.WeatherQuery{CityName: "london", Limit: 20}
+	paris := domain.WeatherQuery{CityName: "paris", Limit: 20}
+
+	t.Run("caches-pages-per-query", func(t *testing.T) {
+		// Arrange
+		real := mocks.NewWeatherRepository(t)
+		repo := newCachedWeatherRepo(real, newFakeCache(), time.Hour)
+		real.On("List", mock.Anything, london).Return(&domain.WeatherPage{Items: []domain.Weather{{CityName: "london"}}, NextCursor: "abc"}, nil).Once()
+		real.On("List", mock.Anything, paris).Return(&domain.WeatherPage{}, nil).Once()
+
+		// Act
+		_, err := repo.List(ctx, london)
+		require.NoError(t, err)
+		page, err := repo.List(ctx, london)
+		require.NoError(t, err)
+		_, err = repo.List(ctx, paris)
+		require.NoError(t, err)
+
+		// Assert
+		assert.Len(t, page.Items, 1)
+		assert.Equal(t, "abc", page.NextCursor)
+	})
+
+	t.Run("writes-invalidate-pages", func(t *testing.T) {
+		real := mocks.NewWeatherRepository(t)
+		repo := newCachedWeatherRepo(real, newFakeCache(), time.Hour)
+		created := &domain.Weather{ID: uuid.New(), CityID: &cityID, CityName: "london"}
+		real.On("List", mock.Anything, london).Return(&domain.WeatherPage{}, nil).Once()
+		real.On("Update", mock.Anything, created).Return(nil)
+		real.On("List", mock.Anything, london).Return(&domain.WeatherPage{Items: []domain.Weather{*created}}, nil).Once()
+
+		_, err := repo.List(ctx, london)
+		require.NoError(t, err)
+		require.NoError(t, repo.Update(ctx, created))
+
+		page, err := repo.List(ctx, london)
+		require.NoError(t, err)
+		assert.Len(t, page.Items, 1)
+	})
+}
+
+func TestCachedWeatherRepo_RetryQueue(t *testing.T) {
+	ctx := context.Background()
+	londonID := uuid.New()
+	london := &domain.Weather{ID: uuid.New(), CityID: &londonID, CityName: "london"}
+
+	t.Run("queues-failed-invalidations", func(t *testing.T) {
+		// Arrange
+		cache := &flakyCache{fakeCache: newFakeCache()}
+		cache.down.Store(true)
+		real := mocks.NewWeatherRepository(t)
+		real.On("GetByID", mock.Anything, london.ID).Return(london, nil).Once()
+		real.On("Delete", mock.Anything, london.ID).Return(nil).Once()
+
+		queue := mocks.NewCacheInvalidationRepository(t)
+		var queued []string
+		queue.On("Create", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				inv := args.Get(1).(*domain.CacheInvalidation)
+				queued = append(queued, string(inv.Op)+" "+inv.Key)
+			}).
+			Return(nil)
+		repo := newCachedWeatherRepo(real, cache, time.Hour, WithRetryQueue(queue))
+
+		// Act
+		err := repo.Delete(ctx, london.ID)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"del weather:" + london.ID.String(),
+			"incr weather:city:" + londonID.String() + ":gen",
+			"incr weather:list:gen",
+		}, queued)
+	})
+
+	t.Run("queues-failed-writes-as-deletes", func(t *testing.T) {
+		cache := &flakyCache{fakeCache: newFakeCache()}
+		cache.down.Store(true)
+		real := mocks.NewWeatherRepository(t)
+		real.On("Update", mock.Anything, london).Return(nil).Once()
+
+		queue := mocks.NewCacheInvalidationRepository(t)
+		queue.On("Create", mock.Anything, mock.MatchedBy(func(inv *domain.CacheInvalidation) bool {
+			return inv.Op == domain.CacheOpDelete && inv.Key == "weather:"+london.ID.String()
+		})).Return(nil).Once()
+		queue.On("Create", mock.Anything, mock.MatchedBy(func(inv *domain.CacheInvalidation) bool {
+			return inv.Op == domain.CacheOpIncr
+		})).Return(nil).Twice()
+		repo := newCachedWeatherRepo(real, cache, time.Hour, WithRetryQueue(queue))
+
+		require.NoError(t, repo.Update(ctx, london))
+	})
+
+	t.Run("invalidates-after-caller-gives-up", func(t *testing.T) {
+		cache := newFakeCache()
+		cacheWeather(t, cache, london, time.Now().Add(time.Hour), 0)
+		reqCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		real := mocks.NewWeatherRepository(t)
+		real.On("GetByID", mock.Anything, london.ID).Return(london, nil).Once()
+		// The request is cancelled, say on shutdown, once the record is deleted.
+		real.On("Delete", mock.Anything, london.ID).Run(func(mock.Arguments) { cancel() }).Return(nil).Once()
+		repo := newCachedWeatherRepo(real, cache, time.Hour, WithRetryQueue(mocks.NewCacheInvalidationRepository(t)))
+
+		err := repo.Delete(reqCtx, london.ID)
+
+		require.NoError(t, err)
+		assert.False(t, cache.has(repo.fmtKey(london.ID)))
+		gen, _ := repo.generation(ctx, repo.cityGenKey(londonID))
+		assert.Equal(t, "1", gen)
+	})
+
+	t.Run("replays-invalidations", func(t *testing.T) {
+		cache := newFakeCache()
+		cacheWeather(t, cache, london, time.Now().Add(time.Hour), 0)
+		repo := newCachedWeatherRepo(mocks.NewWeatherRepository(t), cache, time.Hour)
+
+		err := repo.Invalidate(ctx, &domain.CacheInvalidation{Op: domain.CacheOpDelete, Key: repo.fmtKey(london.ID)})
+		require.NoError(t, err)
+		err = repo.Invalidate(ctx, &domain.CacheInvalidation{Op: domain.CacheOpIncr, Key: listGenKey})
+		require.NoError(t, err)
+
+		assert.False(t, cache.has(repo.fmtKey(london.ID)))
+		gen, _ := repo.generation(ctx, listGenKey)
+		assert.Equal(t, "1", gen)
+
+		err = repo.Invalidate(ctx, &domain.CacheInvalidation{Op: "flush", Key: listGenKey})
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+
+	t.Run("replayed-deletes-evict-local-copies", func(t *testing.T) {
+		cache := newFakeCache()
+		hub := newMemHub()
+		a := newCachedWeatherRepo(mocks.NewWeatherRepository(t), cache, time.Hour, WithLocalCache(10, time.Minute))
+		a.bus = &memBus{hub: hub, origin: "a"}
+		b := newCachedWeatherRepo(mocks.NewWeatherRepository(t), cache, time.Hour, WithLocalCache(10, time.Minute))
+		b.bus = &memBus{hub: hub, origin: "b"}
+		a.local.add(london, time.Now())
+		b.local.add(london, time.Now())
+
+		runCtx, stop := context.WithCancel(ctx)
+		defer stop()
+		go b.Run(runCtx)
+		require.Eventually(t, func() bool { return hub.subscribers() == 1 }, time.Second, time.Millisecond)
+
+		err := a.Invalidate(ctx, &domain.CacheInvalidation{Op: domain.CacheOpDelete, Key: a.fmtKey(london.ID)})
+
+		require.NoError(t, err)
+		_, ok := a.local.get(london.ID, time.Now())
+		assert.False(t, ok)
+		require.Eventually(t, func() bool {
+			_, ok := b.local.get(london.ID, time.Now())
+			return !ok
+		}, time.Second, time.Millisecond)
+	})
+}
@@ -0,0 +1,88 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/internal/repository/mocks"
+	"github.com/xoltawn/weatherhub/internal/service"
+)
+
+func TestAPIKeyService_CreateAndAuthenticate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("round-trip", func(t *testing.T) {
+		// Arrange
+		mockRepo := mocks.NewAPIKeyRepository(t)
+		svc := service.NewAPIKeyService(mockRepo)
+
+		var stored *domain.APIKey
+		mockRepo.On("Create", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { stored = args.Get(1).(*domain.APIKey) }).
+			Return(nil)
+
+		// Act
+		key, plaintext, err := svc.Create(ctx, "ingestion", []string{domain.ScopeWeatherWrite}, nil)
+		require.NoError(t, err)
+
+		mockRepo.On("GetByHash", mock.Anything, stored.KeyHash).Return(stored, nil)
+		mockRepo.On("TouchLastUsed", mock.Anything, stored.ID, mock.Anything).Return(nil)
+
+		identity, err := svc.Authenticate(ctx, plaintext)
+
+		// Assert
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(plaintext, key.Prefix))
+		assert.NotContains(t, key.KeyHash, plaintext)
+		assert.Equal(t, []string{domain.ScopeWeatherWrite}, identity.Scopes)
+	})
+
+	t.Run("unknown-scope", func(t *testing.T) {
+		svc := service.NewAPIKeyService(mocks.NewAPIKeyRepository(t))
+
+		_, _, err := svc.Create(ctx, "bad", []string{"weather:everything"}, nil)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+
+	t.Run("revoked", func(t *testing.T) {
+		mockRepo := mocks.NewAPIKeyRepository(t)
+		svc := service.NewAPIKeyService(mockRepo)
+
+		revokedAt := time.Now().Add(-time.Hour)
+		mockRepo.On("GetByHash", mock.Anything, mock.Anything).Return(&domain.APIKey{RevokedAt: &revokedAt}, nil)
+
+		_, err := svc.Authenticate(ctx, "wh_revoked")
+
+		assert.ErrorIs(t, err, domain.ErrUnauthorized)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		mockRepo := mocks.NewAPIKeyRepository(t)
+		svc := service.NewAPIKeyService(mockRepo)
+
+		expiresAt := time.Now().Add(-time.Minute)
+		mockRepo.On("GetByHash", mock.Anything, mock.Anything).Return(&domain.APIKey{ExpiresAt: &expiresAt}, nil)
+
+		_, err := svc.Authenticate(ctx, "wh_expired")
+
+		assert.ErrorIs(t, err, domain.ErrUnauthorized)
+	})
+
+	t.Run("unknown-key", func(t *testing.T) {
+		mockRepo := mocks.NewAPIKeyRepository(t)
+		svc := service.NewAPIKeyService(mockRepo)
+
+		mockRepo.On("GetByHash", mock.Anything, mock.Anything).Return(nil, domain.ErrNotFound)
+
+		_, err := svc.Authenticate(ctx, "wh_unknown")
+
+		assert.ErrorIs(t, err, domain.ErrUnauthorized)
+	})
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/xoltawn/weatherhub/internal/domain"
+)
+
+type CacheInvalidationReplayerConfig struct {
+	// PollInterval is how often due invalidations are looked up. It defaults to 5 seconds.
+	PollInterval time.Duration
+	// BatchSize bounds the invalidations replayed per poll. It defaults to 100.
+	BatchSize int
+	// BaseDelay is the delay before the first retry; it doubles on every further retry. It
+	// defaults to a second.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between retries. Invalidations are retried until they succeed. It
+	// defaults to a minute.
+	MaxDelay time.Duration
+	// Leader, if set, keeps replicas other than the leader from replaying, so that each queued
+	// invalidation is applied by one replica at a time.
+	Leader domain.Leader
+}
+
+// CacheInvalidationReplayer replays the cache invalidations that failed, with backoff, until
+// they succeed.
+type CacheInvalidationReplayer struct {
+	repo  domain.CacheInvalidationRepository
+	cache domain.CacheInvalidator
+	cfg   CacheInvalidationReplayerConfig
+}
+
+func NewCacheInvalidationReplayer(repo domain.CacheInvalidationRepository, cache domain.CacheInvalidator, cfg CacheInvalidationReplayerConfig) *CacheInvalidationReplayer {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 5 * time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.BaseDelay <= 0 {
+		cfg.BaseDelay = time.Second
+	}
+	if cfg.MaxDelay <= 0 {
+		cfg.MaxDelay = time.Minute
+	}
+
+	return &CacheInvalidationReplayer{
+		repo:  repo,
+		cache: cache,
+		cfg:   cfg,
+	}
+}
+
+// Run replays due invalidations every poll interval until ctx is done.
+func (r *CacheInvalidationReplayer) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		r.replayDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *CacheInvalidationReplayer) replayDue(ctx context.Context) {
+	if r.cfg.Leader != nil && !r.cfg.Leader.IsLeader() {
+		return
+	}
+
+	now := time.Now()
+	invalidations, err := r.repo.GetDue(ctx, now, r.cfg.BatchSize)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("cache invalidations: failed to load due invalidations: %v", err)
+		}
+		return
+	}
+
+	for _, inv := range invalidations {
+		if err := r.cache.Invalidate(ctx, &inv); err != nil {
+			next := now.Add(r.backoff(inv.Attempts + 1))
+			if err := r.repo.RecordAttempt(ctx, inv.ID, next, err.Error()); err != nil {
+				log.Printf("cache invalidations: failed to record attempt on %s %s: %v", inv.Op, inv.Key, err)
+			}
+			continue
+		}
+
+		// An invalidation recorded again while this one was replayed stays, for the next poll.
+		if err := r.repo.Delete(ctx, inv.ID, inv.Version); err != nil {
+			log.Printf("cache invalidations: failed to delete replayed %s %s: %v", inv.Op, inv.Key, err)
+		}
+	}
+}
+
+// backoff returns how long to wait after the attempt-th failed retry.
+func (r *CacheInvalidationReplayer) backoff(attempt int) time.Duration {
+	delay := r.cfg.BaseDelay << (attempt - 1)
+	if delay <= 0 || delay > r.cfg.MaxDelay {
+		delay = r.cfg.MaxDelay
+	}
+
+	return delay
+}
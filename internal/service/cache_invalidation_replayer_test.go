@@ -0,0 +1,104 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/internal/repository/mocks"
+	"github.com/xoltawn/weatherhub/internal/service"
+)
+
+// invalidatorFunc is a CacheInvalidator.
+type invalidatorFunc func(ctx context.Context, inv *domain.CacheInvalidation) error
+
+func (f invalidatorFunc) Invalidate(ctx context.Context, inv *domain.CacheInvalidation) error {
+	return f(ctx, inv)
+}
+
+func TestCacheInvalidationReplayer_Run(t *testing.T) {
+	cfg := service.CacheInvalidationReplayerConfig{
+		PollInterval: 10 * time.Millisecond,
+		BatchSize:    10,
+		BaseDelay:    time.Second,
+		MaxDelay:     time.Minute,
+	}
+	del := domain.CacheInvalidation{ID: uuid.New(), Op: domain.CacheOpDelete, Key: "weather:1", Version: 3}
+
+	t.Run("removes-replayed-invalidations", func(t *testing.T) {
+		// Arrange
+		mockRepo := mocks.NewCacheInvalidationRepository(t)
+		var replayed []string
+		cache := invalidatorFunc(func(ctx context.Context, inv *domain.CacheInvalidation) error {
+			replayed = append(replayed, inv.Key)
+			return nil
+		})
+		r := service.NewCacheInvalidationReplayer(mockRepo, cache, cfg)
+
+		mockRepo.On("GetDue", mock.Anything, mock.Anything, 10).Return([]domain.CacheInvalidation{del}, nil).Once()
+		mockRepo.On("GetDue", mock.Anything, mock.Anything, 10).Return(nil, nil)
+		mockRepo.On("Delete", mock.Anything, del.ID, 3).Return(nil).Once()
+
+		// Act
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		r.Run(ctx)
+
+		// Assert
+		assert.Equal(t, []string{del.Key}, replayed)
+	})
+
+	t.Run("backs-off-failed-attempts", func(t *testing.T) {
+		mockRepo := mocks.NewCacheInvalidationRepository(t)
+		cache := invalidatorFunc(func(ctx context.Context, inv *domain.CacheInvalidation) error {
+			return errors.New("dial tcp 127.0.0.1:6379: connect: connection refused")
+		})
+		r := service.NewCacheInvalidationReplayer(mockRepo, cache, cfg)
+
+		retried := del
+		retried.Attempts = 2
+		exhausted := domain.CacheInvalidation{ID: uuid.New(), Op: domain.CacheOpIncr, Key: "weather:list:gen", Attempts: 30}
+
+		mockRepo.On("GetDue", mock.Anything, mock.Anything, 10).Return([]domain.CacheInvalidation{retried, exhausted}, nil).Once()
+		mockRepo.On("GetDue", mock.Anything, mock.Anything, 10).Return(nil, nil)
+		mockRepo.On("RecordAttempt", mock.Anything, retried.ID, mock.MatchedBy(func(next time.Time) bool {
+			return next.Sub(time.Now()).Round(time.Second) == 4*time.Second
+		}), "dial tcp 127.0.0.1:6379: connect: connection refused").Return(nil).Once()
+		mockRepo.On("RecordAttempt", mock.Anything, exhausted.ID, mock.MatchedBy(func(next time.Time) bool {
+			return next.Sub(time.Now()).Round(time.Second) == time.Minute
+		}), mock.Anything).Return(nil).Once()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		r.Run(ctx)
+	})
+
+	t.Run("defaults-non-positive-settings", func(t *testing.T) {
+		mockRepo := mocks.NewCacheInvalidationRepository(t)
+		r := service.NewCacheInvalidationReplayer(mockRepo, invalidatorFunc(nil), service.CacheInvalidationReplayerConfig{})
+
+		mockRepo.On("GetDue", mock.Anything, mock.Anything, 100).Return(nil, nil).Once()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		r.Run(ctx)
+	})
+
+	t.Run("followers-stay-idle", func(t *testing.T) {
+		mockRepo := mocks.NewCacheInvalidationRepository(t)
+		followerCfg := cfg
+		followerCfg.Leader = fixedLeader(false)
+		r := service.NewCacheInvalidationReplayer(mockRepo, invalidatorFunc(nil), followerCfg)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		r.Run(ctx)
+
+		mockRepo.AssertNotCalled(t, "GetDue", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
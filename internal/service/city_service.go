@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/xoltawn/weatherhub/internal/domain"
+)
+
+type cityService struct {
+	repo   domain.CityRepository
+	places domain.PlaceRepository
+}
+
+func NewCityService(repo domain.CityRepository, places domain.PlaceRepository) domain.CityService {
+	return &cityService{
+		repo:   repo,
+		places: places,
+	}
+}
+
+func (s *cityService) Find(ctx context.Context, name, country string) ([]domain.City, error) {
+	return s.repo.FindByName(ctx, name, strings.ToLower(country))
+}
+
+func (s *cityService) GetByID(ctx context.Context, id uuid.UUID) (*domain.City, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *cityService) Search(ctx context.Context, query, country string, limit int) ([]domain.Place, error) {
+	if limit <= 0 {
+		return nil, domain.ErrInvalidInput
+	}
+
+	return s.places.Search(ctx, query, strings.ToLower(country), limit)
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"golang.org/x/sync/singleflight"
+)
+
+// forecastMaxAge is how long a stored run is served before a new one is fetched. OpenWeatherMap
+// recomputes its forecast every few hours, so fetching more often gains little.
+const forecastMaxAge = time.Hour
+
+type forecastService struct {
+	repo     domain.ForecastRepository
+	provider domain.ForecastProvider
+	// fetches collapses concurrent refreshes of the same location into one upstream call.
+	fetches singleflight.Group
+}
+
+func NewForecastService(repo domain.ForecastRepository, provider domain.ForecastProvider) domain.ForecastService {
+	return &forecastService{
+		repo:     repo,
+		provider: provider,
+	}
+}
+
+func (s *forecastService) GetForecast(ctx context.Context, cityName, country string, units domain.Unit, hours int) (*domain.Forecast, error) {
+	if hours <= 0 {
+		return nil, domain.ErrInvalidInput
+	}
+
+	cityName = strings.ToLower(cityName)
+	country = strings.ToLower(country)
+
+	forecast, err := s.repo.GetLatest(ctx, cityName, country, units)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+
+	now := time.Now()
+	if forecast == nil || now.Sub(forecast.FetchedAt) > forecastMaxAge {
+		fetched, err := s.fetchShared(ctx, cityName, country, units)
+		switch {
+		case forecast != nil && errors.Is(err, domain.ErrThirdParty):
+			// A stale run beats no forecast while the provider is down.
+			log.Printf("forecast: serving the run fetched at %s for %s,%s, the refresh failed: %v",
+				forecast.FetchedAt.Format(time.RFC3339), cityName, country, err)
+		case err != nil:
+			return nil, err
+		default:
+			// The fetched run is shared with the other callers, so its steps are left alone.
+			copied := *fetched
+			forecast = &copied
+		}
+	}
+
+	until := now.Add(time.Duration(hours) * time.Hour)
+	steps := make([]domain.ForecastStep, 0, len(forecast.Steps))
+	for _, step := range forecast.Steps {
+		if step.ValidAt.After(now) && !step.ValidAt.After(until) {
+			steps = append(steps, step)
+		}
+	}
+	forecast.Steps = steps
+
+	return forecast, nil
+}
+
+// fetchShared fetches and stores the forecast of a location, sharing the result with the callers
+// asking for the same location meanwhile. The fetch isn't cancelled with the caller that started
+// it, since others may be waiting on it.
+func (s *forecastService) fetchShared(ctx context.Context, cityName, country string, units domain.Unit) (*domain.Forecast, error) {
+	key := cityName + "\x00" + country + "\x00" + string(units)
+	result := s.fetches.DoChan(key, func() (any, error) {
+		return s.fetchAndStore(context.WithoutCancel(ctx), cityName, country, units)
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-result:
+		if r.Err != nil {
+			return nil, r.Err
+		}
+		return r.Val.(*domain.Forecast), nil
+	}
+}
+
+func (s *forecastService) fetchAndStore(ctx context.Context, cityName, country string, units domain.Unit) (*domain.Forecast, error) {
+	data, err := s.provider.GetHourlyForecast(ctx, cityName, country, units)
+	if err != nil {
+		return nil, err
+	}
+
+	forecast := &domain.Forecast{
+		ID:        uuid.New(),
+		CityName:  cityName,
+		Country:   country,
+		Unit:      units,
+		Provider:  data.Provider,
+		IssuedAt:  data.IssuedAt,
+		FetchedAt: time.Now(),
+		Steps:     data.Steps,
+	}
+	for i := range forecast.Steps {
+		forecast.Steps[i].ForecastID = forecast.ID
+	}
+
+	if err := s.repo.Create(ctx, forecast); err != nil {
+		return nil, err
+	}
+
+	return forecast, nil
+}
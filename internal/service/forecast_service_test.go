@@ -0,0 +1,176 @@
+package service_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/internal/repository/mocks"
+	"github.com/xoltawn/weatherhub/internal/service"
+)
+
+type stubForecastProvider struct {
+	data  *domain.ForecastData
+	err   error
+	delay time.Duration
+	calls atomic.Int32
+}
+
+func (p *stubForecastProvider) GetHourlyForecast(ctx context.Context, city, country string, units domain.Unit) (*domain.ForecastData, error) {
+	p.calls.Add(1)
+	time.Sleep(p.delay)
+	return p.data, p.err
+}
+
+// threeHourly returns n steps three hours apart, starting one hour from now.
+func threeHourly(n int) []domain.ForecastStep {
+	start := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	steps := make([]domain.ForecastStep, n)
+	for i := range steps {
+		steps[i] = domain.ForecastStep{ValidAt: start.Add(time.Duration(3*i) * time.Hour), Temperature: float64(i)}
+	}
+
+	return steps
+}
+
+func TestForecastService_GetForecast(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("fetches-and-stores-when-nothing-stored", func(t *testing.T) {
+		// Arrange
+		mockRepo := mocks.NewForecastRepository(t)
+		provider := &stubForecastProvider{data: &domain.ForecastData{Provider: "openweathermap", Steps: threeHourly(40)}}
+		svc := service.NewForecastService(mockRepo, provider)
+
+		mockRepo.On("GetLatest", mock.Anything, "london", "gb", domain.Metric).Return(nil, domain.ErrNotFound)
+
+		var stored *domain.Forecast
+		mockRepo.On("Create", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { stored = args.Get(1).(*domain.Forecast) }).
+			Return(nil)
+
+		// Act
+		forecast, err := svc.GetForecast(ctx, "London", "GB", domain.Metric, 24)
+
+		// Assert
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, provider.calls.Load())
+		assert.Equal(t, "openweathermap", forecast.Provider)
+		// Steps at +1h, +4h, ... +22h fall within 24 hours.
+		assert.Len(t, forecast.Steps, 8)
+		for _, step := range forecast.Steps {
+			assert.Equal(t, stored.ID, step.ForecastID)
+		}
+	})
+
+	t.Run("serves-fresh-stored-run", func(t *testing.T) {
+		mockRepo := mocks.NewForecastRepository(t)
+		provider := &stubForecastProvider{}
+		svc := service.NewForecastService(mockRepo, provider)
+
+		mockRepo.On("GetLatest", mock.Anything, "london", "gb", domain.Metric).
+			Return(&domain.Forecast{FetchedAt: time.Now().Add(-10 * time.Minute), Steps: threeHourly(40)}, nil)
+
+		forecast, err := svc.GetForecast(ctx, "london", "gb", domain.Metric, 48)
+
+		require.NoError(t, err)
+		assert.Zero(t, provider.calls.Load())
+		assert.Len(t, forecast.Steps, 16)
+	})
+
+	t.Run("refreshes-stale-run", func(t *testing.T) {
+		mockRepo := mocks.NewForecastRepository(t)
+		provider := &stubForecastProvider{data: &domain.ForecastData{Steps: threeHourly(40)}}
+		svc := service.NewForecastService(mockRepo, provider)
+
+		mockRepo.On("GetLatest", mock.Anything, "london", "gb", domain.Metric).
+			Return(&domain.Forecast{FetchedAt: time.Now().Add(-2 * time.Hour)}, nil)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		forecast, err := svc.GetForecast(ctx, "london", "gb", domain.Metric, 120)
+
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, provider.calls.Load())
+		assert.Len(t, forecast.Steps, 40)
+	})
+
+	t.Run("serves-stale-run-when-provider-fails", func(t *testing.T) {
+		mockRepo := mocks.NewForecastRepository(t)
+		svc := service.NewForecastService(mockRepo, &stubForecastProvider{err: domain.ErrThirdParty})
+
+		stale := &domain.Forecast{FetchedAt: time.Now().Add(-2 * time.Hour), Steps: threeHourly(40)}
+		mockRepo.On("GetLatest", mock.Anything, "london", "gb", domain.Metric).Return(stale, nil)
+
+		forecast, err := svc.GetForecast(ctx, "london", "gb", domain.Metric, 24)
+
+		require.NoError(t, err)
+		assert.Equal(t, stale.FetchedAt, forecast.FetchedAt)
+		assert.Len(t, forecast.Steps, 8)
+	})
+
+	t.Run("stale-run-is-not-served-on-other-errors", func(t *testing.T) {
+		mockRepo := mocks.NewForecastRepository(t)
+		svc := service.NewForecastService(mockRepo, &stubForecastProvider{data: &domain.ForecastData{Steps: threeHourly(40)}})
+
+		mockRepo.On("GetLatest", mock.Anything, "london", "gb", domain.Metric).
+			Return(&domain.Forecast{FetchedAt: time.Now().Add(-2 * time.Hour), Steps: threeHourly(40)}, nil)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(domain.ErrInternal)
+
+		_, err := svc.GetForecast(ctx, "london", "gb", domain.Metric, 24)
+
+		assert.ErrorIs(t, err, domain.ErrInternal)
+	})
+
+	t.Run("coalesces-concurrent-refreshes", func(t *testing.T) {
+		mockRepo := mocks.NewForecastRepository(t)
+		provider := &stubForecastProvider{data: &domain.ForecastData{Steps: threeHourly(40)}, delay: 50 * time.Millisecond}
+		svc := service.NewForecastService(mockRepo, provider)
+
+		mockRepo.On("GetLatest", mock.Anything, "london", "gb", domain.Metric).Return(nil, domain.ErrNotFound)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+
+		var wg sync.WaitGroup
+		hours := []int{24, 48, 120}
+		results := make([]*domain.Forecast, len(hours))
+		for i := range hours {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i], _ = svc.GetForecast(ctx, "london", "gb", domain.Metric, hours[i])
+			}()
+		}
+		wg.Wait()
+
+		assert.EqualValues(t, 1, provider.calls.Load())
+		for i, want := range []int{8, 16, 40} {
+			require.NotNil(t, results[i])
+			assert.Len(t, results[i].Steps, want, "each caller gets the steps of its own window")
+		}
+	})
+
+	t.Run("provider-error", func(t *testing.T) {
+		mockRepo := mocks.NewForecastRepository(t)
+		svc := service.NewForecastService(mockRepo, &stubForecastProvider{err: domain.ErrThirdParty})
+
+		mockRepo.On("GetLatest", mock.Anything, "london", "gb", domain.Metric).Return(nil, domain.ErrNotFound)
+
+		_, err := svc.GetForecast(ctx, "london", "gb", domain.Metric, 24)
+
+		assert.ErrorIs(t, err, domain.ErrThirdParty)
+	})
+
+	t.Run("invalid-hours", func(t *testing.T) {
+		svc := service.NewForecastService(mocks.NewForecastRepository(t), &stubForecastProvider{})
+
+		_, err := svc.GetForecast(ctx, "london", "gb", domain.Metric, 0)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	})
+}
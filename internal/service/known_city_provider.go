@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/pkg/errutil"
+)
+
+// knownCityProvider refuses lookups of cities the gazetteer doesn't know, so that typos and
+// made-up names never reach the provider or count against its quota.
+type knownCityProvider struct {
+	places domain.PlaceRepository
+	next   domain.WeatherProvider
+}
+
+func NewKnownCityProvider(places domain.PlaceRepository, next domain.WeatherProvider) domain.WeatherProvider {
+	return &knownCityProvider{
+		places: places,
+		next:   next,
+	}
+}
+
+func (p *knownCityProvider) GetForecast(ctx context.Context, city, country string, units domain.Unit) (*domain.WeatherData, error) {
+	known, err := p.places.Exists(ctx, city, strings.ToLower(country))
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case !known && country == "":
+		return nil, errutil.Wrapf(domain.ErrNotFound, "no known city named %q", city)
+	case !known:
+		return nil, errutil.Wrapf(domain.ErrNotFound, "no known city named %q in %s", city, strings.ToUpper(country))
+	}
+
+	return p.next.GetForecast(ctx, city, country, units)
+}
+
+// GetForecastByCoordinates isn't checked: coordinates need no city to be valid.
+func (p *knownCityProvider) GetForecastByCoordinates(ctx context.Context, coords domain.Coordinates, units domain.Unit) (*domain.WeatherData, error) {
+	return p.next.GetForecastByCoordinates(ctx, coords, units)
+}
@@ -0,0 +1,81 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/internal/repository/mocks"
+	"github.com/xoltawn/weatherhub/internal/service"
+)
+
+// countingProvider counts the lookups that got past the check.
+type countingProvider struct {
+	stubWeatherProvider
+	calls int
+}
+
+func (p *countingProvider) GetForecast(ctx context.Context, city, country string, units domain.Unit) (*domain.WeatherData, error) {
+	p.calls++
+	return p.stubWeatherProvider.GetForecast(ctx, city, country, units)
+}
+
+func TestKnownCityProvider_GetForecast(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("known-city", func(t *testing.T) {
+		// Arrange
+		places := mocks.NewPlaceRepository(t)
+		next := &countingProvider{stubWeatherProvider: stubWeatherProvider{data: &domain.WeatherData{CityName: "London"}}}
+		p := service.NewKnownCityProvider(places, next)
+
+		places.On("Exists", mock.Anything, "london", "gb").Return(true, nil)
+
+		// Act
+		data, err := p.GetForecast(ctx, "london", "GB", domain.Metric)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "London", data.CityName)
+		assert.Equal(t, 1, next.calls)
+	})
+
+	t.Run("known-city-in-any-country", func(t *testing.T) {
+		places := mocks.NewPlaceRepository(t)
+		next := &countingProvider{stubWeatherProvider: stubWeatherProvider{data: &domain.WeatherData{CityName: "London"}}}
+		p := service.NewKnownCityProvider(places, next)
+
+		places.On("Exists", mock.Anything, "london", "").Return(true, nil)
+
+		_, err := p.GetForecast(ctx, "london", "", domain.Metric)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, next.calls)
+	})
+
+	t.Run("unknown-city-never-reaches-the-provider", func(t *testing.T) {
+		places := mocks.NewPlaceRepository(t)
+		next := &countingProvider{}
+		p := service.NewKnownCityProvider(places, next)
+
+		places.On("Exists", mock.Anything, "lundon", "gb").Return(false, nil)
+
+		_, err := p.GetForecast(ctx, "lundon", "gb", domain.Metric)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.ErrorContains(t, err, `"lundon" in GB`)
+		assert.Zero(t, next.calls)
+	})
+
+	t.Run("coordinates-are-not-checked", func(t *testing.T) {
+		next := &countingProvider{stubWeatherProvider: stubWeatherProvider{data: &domain.WeatherData{}}}
+		p := service.NewKnownCityProvider(mocks.NewPlaceRepository(t), next)
+
+		_, err := p.GetForecastByCoordinates(ctx, domain.Coordinates{Lat: 51.5, Lon: -0.12}, domain.Metric)
+
+		require.NoError(t, err)
+	})
+}
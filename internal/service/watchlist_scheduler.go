@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"log"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/pkg/cron"
+)
+
+type WatchlistSchedulerConfig struct {
+	// PollInterval is how often due entries are looked up. It defaults to 10 seconds.
+	PollInterval time.Duration
+	// Concurrency bounds the runs in flight. It defaults to 4.
+	Concurrency int
+	// Jitter is the most a run is delayed past its scheduled time, so that entries sharing a
+	// schedule don't all hit the providers in the same second.
+	Jitter time.Duration
+	// RunTimeout bounds a single fetch. It defaults to a minute.
+	RunTimeout time.Duration
+	// Leader, if set, restricts dispatching to the replica that leads; the others stay idle.
+	Leader domain.Leader
+}
+
+// WatchlistScheduler fetches and stores the weather of watchlist entries as they come due.
+type WatchlistScheduler struct {
+	repo    domain.WatchlistRepository
+	weather domain.WeatherService
+	cfg     WatchlistSchedulerConfig
+
+	mu      sync.Mutex
+	running map[uuid.UUID]bool
+	wg      sync.WaitGroup
+}
+
+func NewWatchlistScheduler(repo domain.WatchlistRepository, weather domain.WeatherService, cfg WatchlistSchedulerConfig) *WatchlistScheduler {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 10 * time.Second
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 4
+	}
+	if cfg.RunTimeout <= 0 {
+		cfg.RunTimeout = time.Minute
+	}
+
+	return &WatchlistScheduler{
+		repo:    repo,
+		weather: weather,
+		cfg:     cfg,
+		running: make(map[uuid.UUID]bool),
+	}
+}
+
+// Run dispatches due entries every poll interval until ctx is done, then waits for the runs in
+// flight. Runs aren't cancelled with ctx, so that a fetch that reached the provider is stored and
+// recorded; RunTimeout bounds the wait.
+func (s *WatchlistScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+	defer s.wg.Wait()
+
+	for {
+		s.dispatchDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *WatchlistScheduler) dispatchDue(ctx context.Context) {
+	// The leader's writes carry its fencing token, so that a leader deposed mid-tick can't
+	// claim or record runs once another replica has taken over.
+	var token int64
+	if s.cfg.Leader != nil {
+		var leading bool
+		if token, leading = s.cfg.Leader.Token(); !leading {
+			return
+		}
+	}
+
+	s.mu.Lock()
+	free := s.cfg.Concurrency - len(s.running)
+	s.mu.Unlock()
+	if free <= 0 {
+		return
+	}
+
+	now := time.Now()
+	entries, err := s.repo.GetDue(ctx, now, free)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("watchlist: failed to load due entries: %v", err)
+		}
+		return
+	}
+
+	for _, entry := range entries {
+		s.mu.Lock()
+		busy := s.running[entry.ID]
+		s.mu.Unlock()
+		if busy {
+			continue
+		}
+
+		schedule, err := entrySchedule(&entry)
+		if err != nil {
+			log.Printf("watchlist: entry %s has an invalid schedule: %v", entry.ID, err)
+			continue
+		}
+
+		// Claim the run by moving the entry's next run before starting this one. The move only
+		// succeeds from the next run read, and with a token at least as new as the entry's, so
+		// when a replica that lost its lease still dispatches, only one of the two runs the entry.
+		claimed, err := s.repo.Claim(ctx, entry.ID, entry.NextRunAt, s.next(schedule, now), token)
+		if err != nil {
+			log.Printf("watchlist: failed to claim entry %s: %v", entry.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		s.mu.Lock()
+		s.running[entry.ID] = true
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go s.run(context.WithoutCancel(ctx), entry, token)
+	}
+}
+
+func (s *WatchlistScheduler) run(ctx context.Context, entry domain.WatchlistEntry, token int64) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.running, entry.ID)
+		s.mu.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.RunTimeout)
+	defer cancel()
+
+	var runErr string
+	if _, err := s.weather.FetchAndStore(ctx, entry.CityName, entry.Country, entry.Unit); err != nil {
+		runErr = err.Error()
+		log.Printf("watchlist: failed to fetch weather for %s,%s: %v", entry.CityName, entry.Country, err)
+	}
+
+	if err := s.repo.RecordRun(ctx, entry.ID, time.Now(), runErr, token); err != nil {
+		log.Printf("watchlist: failed to record run of entry %s: %v", entry.ID, err)
+	}
+}
+
+// next returns when an entry runs after now, delayed by a random jitter.
+func (s *WatchlistScheduler) next(schedule cron.Schedule, now time.Time) time.Time {
+	next := schedule.Next(now.UTC())
+	if s.cfg.Jitter > 0 {
+		next = next.Add(rand.N(s.cfg.Jitter))
+	}
+
+	return next
+}
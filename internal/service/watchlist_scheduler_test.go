@@ -0,0 +1,221 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/internal/repository/mocks"
+	"github.com/xoltawn/weatherhub/internal/service"
+)
+
+// fetchRecorder is a WeatherService that only implements FetchAndStore.
+type fetchRecorder struct {
+	domain.WeatherService
+
+	mu       sync.Mutex
+	cities   []string
+	inFlight atomic.Int32
+	maxSeen  atomic.Int32
+	delay    time.Duration
+	err      error
+}
+
+func (f *fetchRecorder) FetchAndStore(ctx context.Context, cityName, country string, units domain.Unit) (*domain.Weather, error) {
+	n := f.inFlight.Add(1)
+	defer f.inFlight.Add(-1)
+	if n > f.maxSeen.Load() {
+		f.maxSeen.Store(n)
+	}
+
+	time.Sleep(f.delay)
+
+	f.mu.Lock()
+	f.cities = append(f.cities, cityName)
+	f.mu.Unlock()
+
+	return &domain.Weather{}, f.err
+}
+
+type fixedLeader bool
+
+func (l fixedLeader) IsLeader() bool { return bool(l) }
+
+func (l fixedLeader) Token() (int64, bool) {
+	if !l {
+		return 0, false
+	}
+
+	return 7, true
+}
+
+func TestWatchlistScheduler_Run(t *testing.T) {
+	cfg := service.WatchlistSchedulerConfig{
+		PollInterval: 10 * time.Millisecond,
+		Concurrency:  1,
+		RunTimeout:   time.Second,
+	}
+
+	t.Run("runs-due-entries-one-at-a-time", func(t *testing.T) {
+		// Arrange
+		mockRepo := mocks.NewWatchlistRepository(t)
+		weather := &fetchRecorder{delay: 20 * time.Millisecond}
+		s := service.NewWatchlistScheduler(mockRepo, weather, cfg)
+
+		london := domain.WatchlistEntry{ID: uuid.New(), CityName: "london", Country: "gb", Unit: domain.Metric, Interval: "15m"}
+		paris := domain.WatchlistEntry{ID: uuid.New(), CityName: "paris", Country: "fr", Unit: domain.Metric, Cron: "@hourly"}
+
+		mockRepo.On("GetDue", mock.Anything, mock.Anything, 1).Return([]domain.WatchlistEntry{london}, nil).Once()
+		mockRepo.On("GetDue", mock.Anything, mock.Anything, 1).Return([]domain.WatchlistEntry{paris}, nil).Once()
+		mockRepo.On("GetDue", mock.Anything, mock.Anything, 1).Return(nil, nil)
+
+		var rescheduled sync.Map
+		mockRepo.On("Claim", mock.Anything, mock.Anything, mock.Anything, mock.Anything, int64(0)).
+			Run(func(args mock.Arguments) { rescheduled.Store(args.Get(1), args.Get(3)) }).
+			Return(true, nil)
+		mockRepo.On("RecordRun", mock.Anything, mock.Anything, mock.Anything, "", int64(0)).Return(nil)
+
+		// Act
+		ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+		defer cancel()
+		s.Run(ctx)
+
+		// Assert
+		assert.ElementsMatch(t, []string{"london", "paris"}, weather.cities)
+		assert.EqualValues(t, 1, weather.maxSeen.Load())
+
+		next, _ := rescheduled.Load(london.ID)
+		assert.WithinDuration(t, time.Now().Add(15*time.Minute), next.(time.Time), time.Second)
+		next, _ = rescheduled.Load(paris.ID)
+		assert.Zero(t, next.(time.Time).Minute())
+	})
+
+	t.Run("records-failures", func(t *testing.T) {
+		mockRepo := mocks.NewWatchlistRepository(t)
+		weather := &fetchRecorder{err: errors.New("upstream returned status 502")}
+		s := service.NewWatchlistScheduler(mockRepo, weather, cfg)
+
+		entry := domain.WatchlistEntry{ID: uuid.New(), CityName: "london", Country: "gb", Unit: domain.Metric, Interval: "1m"}
+		mockRepo.On("GetDue", mock.Anything, mock.Anything, 1).Return([]domain.WatchlistEntry{entry}, nil).Once()
+		mockRepo.On("GetDue", mock.Anything, mock.Anything, 1).Return(nil, nil)
+		mockRepo.On("Claim", mock.Anything, entry.ID, mock.Anything, mock.Anything, int64(0)).Return(true, nil)
+		mockRepo.On("RecordRun", mock.Anything, entry.ID, mock.Anything, "upstream returned status 502", int64(0)).Return(nil).Once()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		s.Run(ctx)
+	})
+
+	t.Run("waits-for-runs-in-flight", func(t *testing.T) {
+		mockRepo := mocks.NewWatchlistRepository(t)
+		weather := &fetchRecorder{delay: 100 * time.Millisecond}
+		s := service.NewWatchlistScheduler(mockRepo, weather, cfg)
+
+		entry := domain.WatchlistEntry{ID: uuid.New(), CityName: "london", Country: "gb", Unit: domain.Metric, Interval: "1m"}
+		mockRepo.On("GetDue", mock.Anything, mock.Anything, 1).Return([]domain.WatchlistEntry{entry}, nil).Once()
+		mockRepo.On("Claim", mock.Anything, entry.ID, mock.Anything, mock.Anything, int64(0)).Return(true, nil)
+		mockRepo.On("RecordRun", mock.Anything, entry.ID, mock.Anything, "", int64(0)).Return(nil).Once()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			s.Run(ctx)
+			close(done)
+		}()
+
+		time.Sleep(5 * time.Millisecond)
+		cancel()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("Run did not return after cancellation")
+		}
+		assert.Equal(t, []string{"london"}, weather.cities)
+	})
+
+	t.Run("claims-each-run-once", func(t *testing.T) {
+		// Two schedulers both consider themselves leader, as when one lost its lease mid-tick.
+		mockRepo := mocks.NewWatchlistRepository(t)
+		weather := &fetchRecorder{}
+		a := service.NewWatchlistScheduler(mockRepo, weather, cfg)
+		b := service.NewWatchlistScheduler(mockRepo, weather, cfg)
+
+		dueAt := time.Now().Add(-time.Second)
+		entry := domain.WatchlistEntry{ID: uuid.New(), CityName: "london", Country: "gb", Unit: domain.Metric, Interval: "1m", NextRunAt: dueAt}
+		mockRepo.On("GetDue", mock.Anything, mock.Anything, 1).Return([]domain.WatchlistEntry{entry}, nil).Twice()
+		mockRepo.On("GetDue", mock.Anything, mock.Anything, 1).Return(nil, nil)
+		mockRepo.On("Claim", mock.Anything, entry.ID, dueAt, mock.Anything, int64(0)).Return(true, nil).Once()
+		mockRepo.On("Claim", mock.Anything, entry.ID, dueAt, mock.Anything, int64(0)).Return(false, nil).Once()
+		mockRepo.On("RecordRun", mock.Anything, entry.ID, mock.Anything, "", int64(0)).Return(nil).Once()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		var wg sync.WaitGroup
+		for _, s := range []*service.WatchlistScheduler{a, b} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.Run(ctx)
+			}()
+		}
+		wg.Wait()
+
+		weather.mu.Lock()
+		defer weather.mu.Unlock()
+		assert.Equal(t, []string{"london"}, weather.cities)
+	})
+
+	t.Run("followers-stay-idle", func(t *testing.T) {
+		mockRepo := mocks.NewWatchlistRepository(t)
+		weather := &fetchRecorder{}
+		followerCfg := cfg
+		followerCfg.Leader = fixedLeader(false)
+		s := service.NewWatchlistScheduler(mockRepo, weather, followerCfg)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		s.Run(ctx)
+
+		mockRepo.AssertNotCalled(t, "GetDue", mock.Anything, mock.Anything, mock.Anything)
+		assert.Empty(t, weather.cities)
+	})
+
+	t.Run("defaults-non-positive-settings", func(t *testing.T) {
+		mockRepo := mocks.NewWatchlistRepository(t)
+		s := service.NewWatchlistScheduler(mockRepo, &fetchRecorder{}, service.WatchlistSchedulerConfig{PollInterval: 0, Concurrency: -1})
+
+		mockRepo.On("GetDue", mock.Anything, mock.Anything, 4).Return(nil, nil).Once()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		s.Run(ctx)
+	})
+
+	t.Run("fences-the-leaders-writes", func(t *testing.T) {
+		mockRepo := mocks.NewWatchlistRepository(t)
+		weather := &fetchRecorder{}
+		leaderCfg := cfg
+		leaderCfg.Leader = fixedLeader(true)
+		s := service.NewWatchlistScheduler(mockRepo, weather, leaderCfg)
+
+		entry := domain.WatchlistEntry{ID: uuid.New(), CityName: "london", Country: "gb", Unit: domain.Metric, Interval: "1m"}
+		mockRepo.On("GetDue", mock.Anything, mock.Anything, 1).Return([]domain.WatchlistEntry{entry}, nil).Once()
+		mockRepo.On("GetDue", mock.Anything, mock.Anything, 1).Return(nil, nil)
+		mockRepo.On("Claim", mock.Anything, entry.ID, mock.Anything, mock.Anything, int64(7)).Return(true, nil).Once()
+		mockRepo.On("RecordRun", mock.Anything, entry.ID, mock.Anything, "", int64(7)).Return(nil).Once()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		s.Run(ctx)
+
+		assert.Equal(t, []string{"london"}, weather.cities)
+	})
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/pkg/cron"
+	"github.com/xoltawn/weatherhub/pkg/errutil"
+)
+
+type watchlistService struct {
+	repo domain.WatchlistRepository
+}
+
+func NewWatchlistService(repo domain.WatchlistRepository) domain.WatchlistService {
+	return &watchlistService{repo: repo}
+}
+
+func (s *watchlistService) Create(ctx context.Context, entry *domain.WatchlistEntry) (*domain.WatchlistEntry, error) {
+	schedule, err := entrySchedule(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	created := &domain.WatchlistEntry{
+		ID:        uuid.New(),
+		CityName:  strings.ToLower(entry.CityName),
+		Country:   strings.ToLower(entry.Country),
+		Unit:      entry.Unit,
+		Interval:  entry.Interval,
+		Cron:      entry.Cron,
+		Enabled:   entry.Enabled,
+		NextRunAt: firstRun(schedule, now),
+	}
+
+	if err := s.repo.Create(ctx, created); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (s *watchlistService) Update(ctx context.Context, id uuid.UUID, updates *domain.WatchlistEntry) (*domain.WatchlistEntry, error) {
+	schedule, err := entrySchedule(updates)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// A new schedule, or a paused entry resuming, starts over rather than catching up.
+	if updates.Interval != existing.Interval || updates.Cron != existing.Cron || (updates.Enabled && !existing.Enabled) {
+		existing.NextRunAt = firstRun(schedule, time.Now())
+	}
+
+	existing.CityName = strings.ToLower(updates.CityName)
+	existing.Country = strings.ToLower(updates.Country)
+	existing.Unit = updates.Unit
+	existing.Interval = updates.Interval
+	existing.Cron = updates.Cron
+	existing.Enabled = updates.Enabled
+
+	if err := s.repo.Update(ctx, existing); err != nil {
+		return nil, err
+	}
+
+	return existing, nil
+}
+
+func (s *watchlistService) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *watchlistService) GetByID(ctx context.Context, id uuid.UUID) (*domain.WatchlistEntry, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *watchlistService) GetAll(ctx context.Context) ([]domain.WatchlistEntry, error) {
+	return s.repo.GetAll(ctx)
+}
+
+// entrySchedule parses the schedule of an entry, which must have exactly one of an interval and
+// a cron expression.
+func entrySchedule(entry *domain.WatchlistEntry) (cron.Schedule, error) {
+	switch {
+	case entry.Interval != "" && entry.Cron != "":
+		return nil, errutil.Wrap(domain.ErrInvalidInput, "set either interval or cron, not both")
+	case entry.Interval != "":
+		interval, err := time.ParseDuration(entry.Interval)
+		if err != nil {
+			return nil, errutil.Wrapf(domain.ErrInvalidInput, "invalid interval %q", entry.Interval)
+		}
+		if interval < domain.MinWatchInterval {
+			return nil, errutil.Wrapf(domain.ErrInvalidInput, "interval must be at least %s", domain.MinWatchInterval)
+		}
+		return cron.Every(interval), nil
+	case entry.Cron != "":
+		expr, err := cron.Parse(entry.Cron)
+		if err != nil {
+			return nil, errutil.Wrap(domain.ErrInvalidInput, err.Error())
+		}
+		if expr.Next(time.Now().UTC()).IsZero() {
+			return nil, errutil.Wrapf(domain.ErrInvalidInput, "cron %q never fires", entry.Cron)
+		}
+		return expr, nil
+	default:
+		return nil, errutil.Wrap(domain.ErrInvalidInput, "set either interval or cron")
+	}
+}
+
+// firstRun returns when a new schedule first runs: right away for intervals, at the next
+// matching time for cron expressions, whose times are in UTC.
+func firstRun(schedule cron.Schedule, now time.Time) time.Time {
+	if _, ok := schedule.(cron.Every); ok {
+		return now
+	}
+
+	return schedule.Next(now.UTC())
+}
@@ -0,0 +1,105 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/internal/repository/mocks"
+	"github.com/xoltawn/weatherhub/internal/service"
+)
+
+func TestWatchlistService_Create(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("interval-runs-right-away", func(t *testing.T) {
+		// Arrange
+		mockRepo := mocks.NewWatchlistRepository(t)
+		svc := service.NewWatchlistService(mockRepo)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		// Act
+		before := time.Now()
+		entry, err := svc.Create(ctx, &domain.WatchlistEntry{CityName: "London", Country: "GB", Unit: domain.Metric, Interval: "15m", Enabled: true})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "london", entry.CityName)
+		assert.Equal(t, "gb", entry.Country)
+		assert.WithinRange(t, entry.NextRunAt, before, time.Now())
+	})
+
+	t.Run("cron-waits-for-its-time", func(t *testing.T) {
+		mockRepo := mocks.NewWatchlistRepository(t)
+		svc := service.NewWatchlistService(mockRepo)
+		mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		entry, err := svc.Create(ctx, &domain.WatchlistEntry{CityName: "london", Country: "gb", Unit: domain.Metric, Cron: "0 * * * *"})
+
+		require.NoError(t, err)
+		assert.True(t, entry.NextRunAt.After(time.Now()))
+		assert.Zero(t, entry.NextRunAt.Minute())
+	})
+
+	invalid := []struct {
+		name  string
+		entry domain.WatchlistEntry
+	}{
+		{name: "no-schedule", entry: domain.WatchlistEntry{}},
+		{name: "both-schedules", entry: domain.WatchlistEntry{Interval: "1h", Cron: "@hourly"}},
+		{name: "malformed-interval", entry: domain.WatchlistEntry{Interval: "hourly"}},
+		{name: "interval-too-short", entry: domain.WatchlistEntry{Interval: "30s"}},
+		{name: "malformed-cron", entry: domain.WatchlistEntry{Cron: "every hour"}},
+		{name: "cron-never-fires", entry: domain.WatchlistEntry{Cron: "0 0 31 2 *"}},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := service.NewWatchlistService(mocks.NewWatchlistRepository(t))
+
+			_, err := svc.Create(ctx, &tt.entry)
+
+			assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		})
+	}
+}
+
+func TestWatchlistService_Update(t *testing.T) {
+	ctx := context.Background()
+	id := uuid.New()
+	scheduled := time.Now().Add(10 * time.Minute)
+
+	existing := func() *domain.WatchlistEntry {
+		return &domain.WatchlistEntry{ID: id, CityName: "london", Country: "gb", Unit: domain.Metric, Interval: "15m", Enabled: true, NextRunAt: scheduled}
+	}
+
+	t.Run("same-schedule-keeps-next-run", func(t *testing.T) {
+		mockRepo := mocks.NewWatchlistRepository(t)
+		svc := service.NewWatchlistService(mockRepo)
+		mockRepo.On("GetByID", mock.Anything, id).Return(existing(), nil)
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+		entry, err := svc.Update(ctx, id, &domain.WatchlistEntry{CityName: "London", Country: "GB", Unit: domain.Imperial, Interval: "15m", Enabled: true})
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.Imperial, entry.Unit)
+		assert.Equal(t, scheduled, entry.NextRunAt)
+	})
+
+	t.Run("new-schedule-starts-over", func(t *testing.T) {
+		mockRepo := mocks.NewWatchlistRepository(t)
+		svc := service.NewWatchlistService(mockRepo)
+		mockRepo.On("GetByID", mock.Anything, id).Return(existing(), nil)
+		mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+		entry, err := svc.Update(ctx, id, &domain.WatchlistEntry{CityName: "london", Country: "gb", Unit: domain.Metric, Interval: "5m", Enabled: true})
+
+		require.NoError(t, err)
+		assert.True(t, entry.NextRunAt.Before(scheduled))
+	})
+}
@@ -2,21 +2,31 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/pkg/errutil"
+	"golang.org/x/sync/singleflight"
 )
 
 type weatherService struct {
 	repo            domain.WeatherRepository
+	cities          domain.CityRepository
 	weatherProvider domain.WeatherProvider
+
+	// fetches collapses concurrent fetches of the same location into one upstream call.
+	fetches singleflight.Group
 }
 
-func NewWeatherService(repo domain.WeatherRepository, weatherProvider domain.WeatherProvider) domain.WeatherService {
+func NewWeatherService(repo domain.WeatherRepository, cities domain.CityRepository, weatherProvider domain.WeatherProvider) domain.WeatherService {
 	return &weatherService{
 		repo:            repo,
+		cities:          cities,
 		weatherProvider: weatherProvider,
 	}
 }
@@ -30,17 +40,53 @@ func (s *weatherService) FetchAndStore(ctx context.Context, cityName, country st
 		return nil, err
 	}
 
+	return s.store(ctx, weatherApiResp, cityName, country, units, weatherApiResp.Coord)
+}
+
+// FetchAndStoreByCoordinates stores the weather at coords under the place name the provider
+// reports, which may be empty for locations away from any city.
+func (s *weatherService) FetchAndStoreByCoordinates(ctx context.Context, coords domain.Coordinates, units domain.Unit) (*domain.Weather, error) {
+	if err := coords.Validate(); err != nil {
+		return nil, err
+	}
+
+	weatherApiResp, err := s.weatherProvider.GetForecastByCoordinates(ctx, coords, units)
+	if err != nil {
+		return nil, err
+	}
+
+	cityName := strings.ToLower(weatherApiResp.CityName)
+	country := strings.ToLower(weatherApiResp.CountryCode)
+
+	return s.store(ctx, weatherApiResp, cityName, country, units, &coords)
+}
+
+func (s *weatherService) store(ctx context.Context, data *domain.WeatherData, cityName, country string, units domain.Unit, coord *domain.Coordinates) (*domain.Weather, error) {
+	var city *domain.City
+	if cityName != "" {
+		var err error
+		if city, err = s.resolveCity(ctx, data, cityName, country); err != nil {
+			return nil, err
+		}
+	}
+
 	weather := &domain.Weather{
 		ID:          uuid.New(),
 		CityName:    cityName,
 		Country:     country,
-		Temperature: weatherApiResp.Temperature,
-		Description: weatherApiResp.Description,
-		Humidity:    weatherApiResp.Humidity,
-		WindSpeed:   weatherApiResp.WindSpeed,
+		Temperature: data.Temperature,
+		Description: data.Description,
+		Humidity:    data.Humidity,
+		WindSpeed:   data.WindSpeed,
+		Provider:    data.Provider,
+		Coord:       coord,
 		FetchedAt:   time.Now(),
 		Unit:        units,
 	}
+	if city != nil {
+		weather.CityID = &city.ID
+		weather.City = city
+	}
 
 	if err := s.repo.Create(ctx, weather); err != nil {
 		return nil, err
@@ -49,8 +95,15 @@ func (s *weatherService) FetchAndStore(ctx context.Context, cityName, country st
 	return weather, nil
 }
 
-func (s *weatherService) GetAllRecords(ctx context.Context) ([]domain.Weather, error) {
-	return s.repo.GetAll(ctx)
+func (s *weatherService) ListRecords(ctx context.Context, query domain.WeatherQuery) (*domain.WeatherPage, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	query.CityName = strings.ToLower(query.CityName)
+	query.Country = strings.ToLower(query.Country)
+
+	return s.repo.List(ctx, query)
 }
 
 func (s *weatherService) GetByID(ctx context.Context, id uuid.UUID) (*domain.Weather, error) {
@@ -80,6 +133,243 @@ func (s *weatherService) DeleteRecord(ctx context.Context, id uuid.UUID) error {
 	return s.repo.Delete(ctx, id)
 }
 
-func (s *weatherService) GetLatest(ctx context.Context, cityName string) (*domain.Weather, error) {
-	return s.repo.GetLatestByCity(ctx, strings.ToLower(cityName))
+func (s *weatherService) GetLatest(ctx context.Context, cityName, country string) (*domain.Weather, error) {
+	city, err := s.findCity(ctx, cityName, country)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetLatestByCity(ctx, city.ID)
+}
+
+func (s *weatherService) GetLatestByCityID(ctx context.Context, cityID uuid.UUID) (*domain.Weather, error) {
+	return s.repo.GetLatestByCity(ctx, cityID)
+}
+
+func (s *weatherService) GetHistory(ctx context.Context, cityName, country string, query domain.WeatherHistoryQuery) (*domain.WeatherHistory, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	city, err := s.findCity(ctx, cityName, country)
+	if err != nil {
+		return nil, err
+	}
+	query.CityID = city.ID
+
+	history := &domain.WeatherHistory{
+		City: city,
+		Unit: query.Unit,
+		From: query.From,
+		To:   query.To,
+	}
+
+	if query.Interval != 0 {
+		history.Interval = query.Interval.String()
+		history.Buckets, err = s.repo.GetHistoryBuckets(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+
+		return history, nil
+	}
+
+	// One extra observation tells whether the window holds too many to return raw.
+	history.Observations, err = s.repo.GetHistory(ctx, query, domain.MaxHistoryPoints+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(history.Observations) > domain.MaxHistoryPoints {
+		return nil, fmt.Errorf("window holds more than %d observations, set an interval: %w", domain.MaxHistoryPoints, domain.ErrInvalidInput)
+	}
+
+	return history, nil
+}
+
+func (s *weatherService) GetCurrent(ctx context.Context, cityName, country string, units domain.Unit, maxAge time.Duration) (*domain.CurrentWeather, error) {
+	if maxAge < 0 {
+		return nil, errutil.Wrap(domain.ErrInvalidInput, "max age must not be negative")
+	}
+
+	cityName = strings.ToLower(cityName)
+	country = strings.ToLower(country)
+
+	city, err := s.findCity(ctx, cityName, country)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		// Never fetched before.
+	case err != nil:
+		return nil, err
+	case maxAge > 0:
+		// The query doesn't depend on maxAge, so that repeated lookups can be cached.
+		query := domain.WeatherQuery{CityID: &city.ID, Unit: units, Limit: 1}
+		if err := query.Validate(); err != nil {
+			return nil, err
+		}
+
+		page, err := s.repo.List(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		if len(page.Items) > 0 && time.Since(page.Items[0].FetchedAt) <= maxAge {
+			return &domain.CurrentWeather{Weather: page.Items[0], Source: domain.SourceStorage}, nil
+		}
+	}
+
+	weather, err := s.fetchShared(ctx, cityName, country, units)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.CurrentWeather{Weather: *weather, Source: domain.SourceProvider}, nil
+}
+
+// fetchShared fetches and stores the weather of a location, sharing the result with the callers
+// asking for the same location meanwhile. The fetch isn't cancelled with the caller that started
+// it, since others may be waiting on it.
+func (s *weatherService) fetchShared(ctx context.Context, cityName, country string, units domain.Unit) (*domain.Weather, error) {
+	key := cityName + "\x00" + country + "\x00" + string(units)
+	result := s.fetches.DoChan(key, func() (any, error) {
+		return s.FetchAndStore(context.WithoutCancel(ctx), cityName, country, units)
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-result:
+		if r.Err != nil {
+			return nil, r.Err
+		}
+		return r.Val.(*domain.Weather), nil
+	}
+}
+
+// findCity returns the one stored city with that name, in country if not empty.
+func (s *weatherService) findCity(ctx context.Context, cityName, country string) (*domain.City, error) {
+	cities, err := s.cities.FindByName(ctx, cityName, strings.ToLower(country))
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(cities) {
+	case 0:
+		return nil, errutil.Wrapf(domain.ErrNotFound, "no city named %q", cityName)
+	case 1:
+		return &cities[0], nil
+	default:
+		return nil, errutil.Wrapf(domain.ErrAmbiguous, "%d cities are named %q, specify a country or use a city ID", len(cities), cityName)
+	}
+}
+
+// resolveCity returns the stored city the provider response refers to, creating it on first
+// sight. Cities are matched by provider ID first, then by canonical name and country; the
+// requested name is kept as an alias when it differs from the canonical one.
+func (s *weatherService) resolveCity(ctx context.Context, data *domain.WeatherData, requestedName, country string) (*domain.City, error) {
+	name := data.CityName
+	if name == "" {
+		name = requestedName
+	}
+	if data.CountryCode != "" {
+		country = strings.ToLower(data.CountryCode)
+	}
+
+	var providerID *string
+	if data.ProviderCityID != "" {
+		id := data.Provider + ":" + data.ProviderCityID
+		providerID = &id
+	}
+
+	city, err := s.lookupCity(ctx, providerID, name, country)
+	if err != nil {
+		return nil, err
+	}
+
+	if city == nil {
+		city = &domain.City{
+			ID:             uuid.New(),
+			ProviderID:     providerID,
+			Name:           name,
+			NormalizedName: domain.NormalizeCityName(name),
+			Country:        country,
+			Coord:          data.Coord,
+			Timezone:       data.Timezone,
+		}
+		addAlias(city, requestedName)
+
+		createErr := s.cities.Create(ctx, city)
+		if createErr == nil {
+			return city, nil
+		}
+		if !errors.Is(createErr, domain.ErrAlreadyExists) {
+			return nil, createErr
+		}
+
+		// A concurrent request created the city first; use theirs.
+		if city, err = s.lookupCity(ctx, providerID, name, country); err != nil {
+			return nil, err
+		}
+		if city == nil {
+			return nil, createErr
+		}
+	}
+
+	changed := addAlias(city, requestedName)
+	if city.ProviderID == nil && providerID != nil {
+		city.ProviderID, changed = providerID, true
+	}
+	if city.Coord == nil && data.Coord != nil {
+		city.Coord, changed = data.Coord, true
+	}
+	if city.Timezone == "" && data.Timezone != "" {
+		city.Timezone, changed = data.Timezone, true
+	}
+
+	if changed {
+		if err := s.cities.Update(ctx, city); err != nil {
+			return nil, err
+		}
+	}
+
+	return city, nil
+}
+
+// lookupCity returns the stored city with the provider ID, or else with the canonical name or an
+// alias in country, or nil if there's none.
+func (s *weatherService) lookupCity(ctx context.Context, providerID *string, name, country string) (*domain.City, error) {
+	if providerID != nil {
+		city, err := s.cities.GetByProviderID(ctx, *providerID)
+		if err == nil {
+			return city, nil
+		}
+		if !errors.Is(err, domain.ErrNotFound) {
+			return nil, err
+		}
+	}
+
+	matches, err := s.cities.FindByName(ctx, name, country)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prefer a canonical name match over an alias match.
+	var city *domain.City
+	for i := range matches {
+		if city == nil || matches[i].NormalizedName == domain.NormalizeCityName(name) {
+			city = &matches[i]
+		}
+	}
+
+	return city, nil
+}
+
+// addAlias records name as an alias of city unless it's already known, and reports whether it did.
+func addAlias(city *domain.City, name string) bool {
+	name = domain.NormalizeCityName(name)
+	if name == city.NormalizedName || slices.Contains(city.Aliases, name) {
+		return false
+	}
+
+	city.Aliases = append(city.Aliases, name)
+
+	return true
 }
@@ -0,0 +1,383 @@
+package service_test
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/internal/repository/mocks"
+	"github.com/xoltawn/weatherhub/internal/service"
+)
+
+type stubWeatherProvider struct {
+	data *domain.WeatherData
+}
+
+func (p *stubWeatherProvider) GetForecast(ctx context.Context, city, country string, units domain.Unit) (*domain.WeatherData, error) {
+	data := *p.data
+	return &data, nil
+}
+
+func (p *stubWeatherProvider) GetForecastByCoordinates(ctx context.Context, coords domain.Coordinates, units domain.Unit) (*domain.WeatherData, error) {
+	data := *p.data
+	return &data, nil
+}
+
+func TestWeatherService_FetchAndStore_ResolvesCity(t *testing.T) {
+	ctx := context.Background()
+	london := &domain.WeatherData{
+		CityName:       "London",
+		CountryCode:    "GB",
+		Provider:       "openweathermap",
+		ProviderCityID: "2643743",
+		Coord:          &domain.Coordinates{Lat: 51.5085, Lon: -0.1257},
+	}
+
+	t.Run("creates-unknown-city", func(t *testing.T) {
+		// Arrange
+		weatherRepo := mocks.NewWeatherRepository(t)
+		cityRepo := mocks.NewCityRepository(t)
+		svc := service.NewWeatherService(weatherRepo, cityRepo, &stubWeatherProvider{data: london})
+
+		cityRepo.On("GetByProviderID", mock.Anything, "openweathermap:2643743").Return(nil, domain.ErrNotFound)
+		cityRepo.On("FindByName", mock.Anything, "London", "gb").Return(nil, nil)
+
+		var created *domain.City
+		cityRepo.On("Create", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { created = args.Get(1).(*domain.City) }).
+			Return(nil)
+		weatherRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		// Act
+		weather, err := svc.FetchAndStore(ctx, "Londres", "GB", domain.Metric)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "London", created.Name)
+		assert.Equal(t, "london", created.NormalizedName)
+		assert.Equal(t, "gb", created.Country)
+		assert.Equal(t, "openweathermap:2643743", *created.ProviderID)
+		assert.Equal(t, []string{"londres"}, created.Aliases)
+		assert.Equal(t, created.ID, *weather.CityID)
+	})
+
+	t.Run("uses-city-created-concurrently", func(t *testing.T) {
+		weatherRepo := mocks.NewWeatherRepository(t)
+		cityRepo := mocks.NewCityRepository(t)
+		svc := service.NewWeatherService(weatherRepo, cityRepo, &stubWeatherProvider{data: london})
+
+		providerID := "openweathermap:2643743"
+		theirs := &domain.City{ID: uuid.New(), ProviderID: &providerID, Name: "London", NormalizedName: "london", Country: "gb"}
+		cityRepo.On("GetByProviderID", mock.Anything, providerID).Return(nil, domain.ErrNotFound).Once()
+		cityRepo.On("FindByName", mock.Anything, "London", "gb").Return(nil, nil).Once()
+		cityRepo.On("Create", mock.Anything, mock.Anything).Return(domain.ErrAlreadyExists).Once()
+		cityRepo.On("GetByProviderID", mock.Anything, providerID).Return(theirs, nil).Once()
+		cityRepo.On("Update", mock.Anything, mock.MatchedBy(func(c *domain.City) bool {
+			return c.ID == theirs.ID && slices.Contains(c.Aliases, "londres")
+		})).Return(nil).Once()
+		weatherRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		weather, err := svc.FetchAndStore(ctx, "Londres", "GB", domain.Metric)
+
+		require.NoError(t, err)
+		assert.Equal(t, theirs.ID, *weather.CityID)
+	})
+
+	t.Run("links-backfilled-city-by-name", func(t *testing.T) {
+		weatherRepo := mocks.NewWeatherRepository(t)
+		cityRepo := mocks.NewCityRepository(t)
+		svc := service.NewWeatherService(weatherRepo, cityRepo, &stubWeatherProvider{data: london})
+
+		existing := domain.City{ID: uuid.New(), Name: "London", NormalizedName: "london", Country: "gb"}
+		cityRepo.On("GetByProviderID", mock.Anything, "openweathermap:2643743").Return(nil, domain.ErrNotFound)
+		cityRepo.On("FindByName", mock.Anything, "London", "gb").Return([]domain.City{existing}, nil)
+
+		var updated *domain.City
+		cityRepo.On("Update", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) { updated = args.Get(1).(*domain.City) }).
+			Return(nil)
+		weatherRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		weather, err := svc.FetchAndStore(ctx, "london", "gb", domain.Metric)
+
+		require.NoError(t, err)
+		assert.Equal(t, existing.ID, *weather.CityID)
+		assert.Equal(t, "openweathermap:2643743", *updated.ProviderID)
+		assert.Equal(t, london.Coord, updated.Coord)
+		assert.Empty(t, updated.Aliases)
+	})
+
+	t.Run("reuses-city-by-provider-id", func(t *testing.T) {
+		weatherRepo := mocks.NewWeatherRepository(t)
+		cityRepo := mocks.NewCityRepository(t)
+		svc := service.NewWeatherService(weatherRepo, cityRepo, &stubWeatherProvider{data: london})
+
+		providerID := "openweathermap:2643743"
+		existing := &domain.City{
+			ID: uuid.New(), ProviderID: &providerID, Name: "London", NormalizedName: "london", Country: "gb",
+			Coord: london.Coord, Timezone: "Europe/London",
+		}
+		cityRepo.On("GetByProviderID", mock.Anything, providerID).Return(existing, nil)
+		weatherRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		weather, err := svc.FetchAndStore(ctx, "london", "gb", domain.Metric)
+
+		require.NoError(t, err)
+		assert.Equal(t, existing.ID, *weather.CityID)
+	})
+}
+
+func TestWeatherService_GetLatest(t *testing.T) {
+	ctx := context.Background()
+
+	londonGB := domain.City{ID: uuid.New(), Name: "London", Country: "gb"}
+	londonCA := domain.City{ID: uuid.New(), Name: "London", Country: "ca"}
+
+	t.Run("ambiguous-without-country", func(t *testing.T) {
+		cityRepo := mocks.NewCityRepository(t)
+		svc := service.NewWeatherService(mocks.NewWeatherRepository(t), cityRepo, nil)
+
+		cityRepo.On("FindByName", mock.Anything, "london", "").Return([]domain.City{londonGB, londonCA}, nil)
+
+		_, err := svc.GetLatest(ctx, "london", "")
+
+		assert.ErrorIs(t, err, domain.ErrAmbiguous)
+	})
+
+	t.Run("disambiguated-by-country", func(t *testing.T) {
+		weatherRepo := mocks.NewWeatherRepository(t)
+		cityRepo := mocks.NewCityRepository(t)
+		svc := service.NewWeatherService(weatherRepo, cityRepo, nil)
+
+		cityRepo.On("FindByName", mock.Anything, "london", "ca").Return([]domain.City{londonCA}, nil)
+		weatherRepo.On("GetLatestByCity", mock.Anything, londonCA.ID).Return(&domain.Weather{CityID: &londonCA.ID}, nil)
+
+		weather, err := svc.GetLatest(ctx, "london", "CA")
+
+		require.NoError(t, err)
+		assert.Equal(t, londonCA.ID, *weather.CityID)
+	})
+
+	t.Run("unknown-city", func(t *testing.T) {
+		cityRepo := mocks.NewCityRepository(t)
+		svc := service.NewWeatherService(mocks.NewWeatherRepository(t), cityRepo, nil)
+
+		cityRepo.On("FindByName", mock.Anything, "atlantis", "").Return(nil, nil)
+
+		_, err := svc.GetLatest(ctx, "atlantis", "")
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
+
+func TestWeatherService_GetHistory(t *testing.T) {
+	ctx := context.Background()
+
+	london := domain.City{ID: uuid.New(), Name: "London", Country: "gb"}
+	to := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	from := to.Add(-24 * time.Hour)
+
+	t.Run("observations", func(t *testing.T) {
+		// Arrange
+		weatherRepo := mocks.NewWeatherRepository(t)
+		cityRepo := mocks.NewCityRepository(t)
+		svc := service.NewWeatherService(weatherRepo, cityRepo, nil)
+
+		cityRepo.On("FindByName", mock.Anything, "london", "gb").Return([]domain.City{london}, nil)
+		weatherRepo.On("GetHistory", mock.Anything, domain.WeatherHistoryQuery{
+			CityID: london.ID, Unit: domain.Metric, From: from, To: to,
+		}, domain.MaxHistoryPoints+1).Return([]domain.Weather{{Temperature: 10}, {Temperature: 12}}, nil)
+
+		// Act
+		history, err := svc.GetHistory(ctx, "london", "GB", domain.WeatherHistoryQuery{To: to})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, london.ID, history.City.ID)
+		assert.Equal(t, from, history.From)
+		assert.Len(t, history.Observations, 2)
+		assert.Empty(t, history.Buckets)
+	})
+
+	t.Run("buckets", func(t *testing.T) {
+		weatherRepo := mocks.NewWeatherRepository(t)
+		cityRepo := mocks.NewCityRepository(t)
+		svc := service.NewWeatherService(weatherRepo, cityRepo, nil)
+
+		query := domain.WeatherHistoryQuery{Unit: domain.Imperial, From: from, To: to, Interval: time.Hour}
+		cityRepo.On("FindByName", mock.Anything, "london", "").Return([]domain.City{london}, nil)
+		weatherRepo.On("GetHistoryBuckets", mock.Anything, mock.MatchedBy(func(q domain.WeatherHistoryQuery) bool {
+			return q.CityID == london.ID && q.Unit == domain.Imperial && q.Interval == time.Hour
+		})).Return([]domain.WeatherBucket{{Start: from, Count: 4, AvgTemperature: 51.5}}, nil)
+
+		history, err := svc.GetHistory(ctx, "london", "", query)
+
+		require.NoError(t, err)
+		assert.Equal(t, "1h0m0s", history.Interval)
+		assert.Len(t, history.Buckets, 1)
+		assert.Nil(t, history.Observations)
+	})
+
+	t.Run("too-many-observations", func(t *testing.T) {
+		weatherRepo := mocks.NewWeatherRepository(t)
+		cityRepo := mocks.NewCityRepository(t)
+		svc := service.NewWeatherService(weatherRepo, cityRepo, nil)
+
+		cityRepo.On("FindByName", mock.Anything, "london", "").Return([]domain.City{london}, nil)
+		weatherRepo.On("GetHistory", mock.Anything, mock.Anything, domain.MaxHistoryPoints+1).
+			Return(make([]domain.Weather, domain.MaxHistoryPoints+1), nil)
+
+		_, err := svc.GetHistory(ctx, "london", "", domain.WeatherHistoryQuery{From: from, To: to})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		assert.ErrorContains(t, err, "set an interval")
+	})
+
+	invalid := []struct {
+		name  string
+		query domain.WeatherHistoryQuery
+	}{
+		{name: "empty-window", query: domain.WeatherHistoryQuery{From: to, To: from}},
+		{name: "interval-too-small", query: domain.WeatherHistoryQuery{From: from, To: to, Interval: time.Second}},
+		{name: "too-many-buckets", query: domain.WeatherHistoryQuery{From: from.Add(-365 * 24 * time.Hour), To: to, Interval: time.Minute}},
+		// 1000 hours from half past spans 1001 hourly buckets.
+		{name: "too-many-aligned-buckets", query: domain.WeatherHistoryQuery{From: to.Add(-1000*time.Hour + 30*time.Minute), To: to.Add(30 * time.Minute), Interval: time.Hour}},
+	}
+
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := service.NewWeatherService(mocks.NewWeatherRepository(t), mocks.NewCityRepository(t), nil)
+
+			_, err := svc.GetHistory(ctx, "london", "", tt.query)
+
+			assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		})
+	}
+}
+
+// gatedProvider counts its calls and holds them until release is closed.
+type gatedProvider struct {
+	stubWeatherProvider
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func (p *gatedProvider) GetForecast(ctx context.Context, city, country string, units domain.Unit) (*domain.WeatherData, error) {
+	p.calls.Add(1)
+	<-p.release
+	return p.stubWeatherProvider.GetForecast(ctx, city, country, units)
+}
+
+func TestWeatherService_GetCurrent(t *testing.T) {
+	ctx := context.Background()
+
+	london := domain.City{ID: uuid.New(), Name: "London", NormalizedName: "london", Country: "gb"}
+	data := &domain.WeatherData{CityName: "London", CountryCode: "GB", Provider: "openmeteo", Temperature: 11}
+
+	t.Run("serves-fresh-record", func(t *testing.T) {
+		// Arrange
+		weatherRepo := mocks.NewWeatherRepository(t)
+		cityRepo := mocks.NewCityRepository(t)
+		svc := service.NewWeatherService(weatherRepo, cityRepo, nil)
+
+		cityRepo.On("FindByName", mock.Anything, "london", "gb").Return([]domain.City{london}, nil)
+		weatherRepo.On("List", mock.Anything, mock.MatchedBy(func(q domain.WeatherQuery) bool {
+			return *q.CityID == london.ID && q.Unit == domain.Imperial && q.Limit == 1
+		})).Return(&domain.WeatherPage{Items: []domain.Weather{{Temperature: 52, FetchedAt: time.Now().Add(-5 * time.Minute)}}}, nil)
+
+		// Act
+		current, err := svc.GetCurrent(ctx, "London", "GB", domain.Imperial, 10*time.Minute)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, domain.SourceStorage, current.Source)
+		assert.Equal(t, 52.0, current.Temperature)
+	})
+
+	t.Run("fetches-when-stale", func(t *testing.T) {
+		weatherRepo := mocks.NewWeatherRepository(t)
+		cityRepo := mocks.NewCityRepository(t)
+		svc := service.NewWeatherService(weatherRepo, cityRepo, &stubWeatherProvider{data: data})
+
+		cityRepo.On("FindByName", mock.Anything, mock.Anything, "gb").Return([]domain.City{london}, nil)
+		weatherRepo.On("List", mock.Anything, mock.Anything).
+			Return(&domain.WeatherPage{Items: []domain.Weather{{Temperature: 9, FetchedAt: time.Now().Add(-2 * time.Minute)}}}, nil)
+		weatherRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		current, err := svc.GetCurrent(ctx, "london", "gb", domain.Metric, time.Minute)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.SourceProvider, current.Source)
+		assert.Equal(t, 11.0, current.Temperature)
+		assert.Equal(t, london.ID, *current.CityID)
+	})
+
+	t.Run("zero-max-age-always-fetches", func(t *testing.T) {
+		weatherRepo := mocks.NewWeatherRepository(t)
+		cityRepo := mocks.NewCityRepository(t)
+		svc := service.NewWeatherService(weatherRepo, cityRepo, &stubWeatherProvider{data: data})
+
+		cityRepo.On("FindByName", mock.Anything, mock.Anything, "gb").Return([]domain.City{london}, nil)
+		weatherRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+		current, err := svc.GetCurrent(ctx, "london", "gb", domain.Metric, 0)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.SourceProvider, current.Source)
+		weatherRepo.AssertNotCalled(t, "List", mock.Anything, mock.Anything)
+	})
+
+	t.Run("collapses-concurrent-fetches", func(t *testing.T) {
+		weatherRepo := mocks.NewWeatherRepository(t)
+		cityRepo := mocks.NewCityRepository(t)
+		provider := &gatedProvider{stubWeatherProvider: stubWeatherProvider{data: data}, release: make(chan struct{})}
+		svc := service.NewWeatherService(weatherRepo, cityRepo, provider)
+
+		cityRepo.On("FindByName", mock.Anything, mock.Anything, "gb").Return(nil, nil)
+		cityRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+		weatherRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
+
+		const callers = 5
+		var wg sync.WaitGroup
+		results := make([]*domain.CurrentWeather, callers)
+		for i := range callers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i], _ = svc.GetCurrent(ctx, "london", "gb", domain.Metric, time.Minute)
+			}()
+		}
+
+		require.Eventually(t, func() bool { return provider.calls.Load() == 1 }, time.Second, time.Millisecond)
+		time.Sleep(20 * time.Millisecond)
+		close(provider.release)
+		wg.Wait()
+
+		assert.EqualValues(t, 1, provider.calls.Load())
+		for _, current := range results {
+			require.NotNil(t, current)
+			assert.Equal(t, domain.SourceProvider, current.Source)
+			assert.Equal(t, results[0].ID, current.ID)
+		}
+	})
+
+	t.Run("ambiguous-without-country", func(t *testing.T) {
+		cityRepo := mocks.NewCityRepository(t)
+		svc := service.NewWeatherService(mocks.NewWeatherRepository(t), cityRepo, nil)
+
+		cityRepo.On("FindByName", mock.Anything, "london", "").
+			Return([]domain.City{london, {ID: uuid.New(), Name: "London", Country: "ca"}}, nil)
+
+		_, err := svc.GetCurrent(ctx, "london", "", domain.Metric, time.Minute)
+
+		assert.ErrorIs(t, err, domain.ErrAmbiguous)
+	})
+}
@@ -0,0 +1,208 @@
+// Package cron parses standard five-field cron expressions and computes when they next fire.
+//
+// Fields are minute, hour, day of month, month and day of week, each a "*", a value, a range
+// ("1-5"), a list ("1,15") or any of those with a step ("*/15", "0-30/10"). Months and days of
+// week also accept three-letter names, and day of week 7 is Sunday like 0. As in Vixie cron, when
+// both day fields are restricted a time matches if either does. Descriptors such as @hourly are
+// supported as well.
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidExpression = errors.New("cron: invalid expression")
+
+// Schedule reports the first activation strictly after t.
+type Schedule interface {
+	Next(t time.Time) time.Time
+}
+
+// Every fires every d, counted from the time it's asked about.
+type Every time.Duration
+
+func (e Every) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
+
+// Expression is a parsed cron expression. It's evaluated in the location of the times passed to Next.
+type Expression struct {
+	minute, hour, dom, month, dow uint64
+	// domStar and dowStar record a "*" day field, which doesn't take part in the either-day rule.
+	domStar, dowStar bool
+}
+
+type field struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = field{min: 0, max: 59}
+	hourField   = field{min: 0, max: 23}
+	domField    = field{min: 1, max: 31}
+	monthField  = field{min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowField = field{min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+func Parse(spec string) (*Expression, error) {
+	spec = strings.TrimSpace(spec)
+	if expanded, ok := descriptors[strings.ToLower(spec)]; ok {
+		spec = expanded
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w: %q has %d fields, want 5", ErrInvalidExpression, spec, len(fields))
+	}
+
+	var (
+		e   Expression
+		err error
+	)
+	if e.minute, err = minuteField.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if e.hour, err = hourField.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if e.dom, err = domField.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if e.month, err = monthField.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if e.dow, err = dowField.parse(fields[4]); err != nil {
+		return nil, err
+	}
+
+	// Sunday is both 0 and 7.
+	if e.dow&(1<<7) != 0 {
+		e.dow |= 1
+	}
+	e.domStar = fields[2] == "*" || strings.HasPrefix(fields[2], "*/")
+	e.dowStar = fields[4] == "*" || strings.HasPrefix(fields[4], "*/")
+
+	return &e, nil
+}
+
+// parse returns the bit set of the values s selects.
+func (f field) parse(s string) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(s, ",") {
+		rng, stepText, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepText); err != nil || step <= 0 {
+				return 0, fmt.Errorf("%w: step %q", ErrInvalidExpression, part)
+			}
+		}
+
+		lo, hi := f.min, f.max
+		if rng != "*" {
+			from, to, isRange := strings.Cut(rng, "-")
+
+			var err error
+			if lo, err = f.value(from); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = f.value(to); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				// "5/15" means from 5 to the end, every 15.
+				hi = f.max
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("%w: range %q", ErrInvalidExpression, part)
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << v
+		}
+	}
+
+	return bits, nil
+}
+
+func (f field) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("%w: %q is not in %d-%d", ErrInvalidExpression, s, f.min, f.max)
+	}
+
+	return v, nil
+}
+
+// searchLimit bounds Next's search; an expression like "0 0 30 2 *" never fires.
+const searchLimit = 5 * 366 * 24 * time.Hour
+
+// Next returns the first matching minute after t, or the zero time if there's none within five years.
+func (e *Expression) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(searchLimit)
+
+	for t.Before(limit) {
+		if e.month&(1<<int(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !e.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if e.hour&(1<<t.Hour()) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if e.minute&(1<<t.Minute()) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (e *Expression) dayMatches(t time.Time) bool {
+	dom := e.dom&(1<<t.Day()) != 0
+	dow := e.dow&(1<<int(t.Weekday())) != 0
+
+	if e.domStar || e.dowStar {
+		return dom && dow
+	}
+
+	return dom || dow
+}
@@ -0,0 +1,93 @@
+package cron_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xoltawn/weatherhub/pkg/cron"
+)
+
+func TestExpression_Next(t *testing.T) {
+	// A Wednesday.
+	from := time.Date(2026, 3, 4, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{spec: "* * * * *", want: time.Date(2026, 3, 4, 10, 8, 0, 0, time.UTC)},
+		{spec: "*/15 * * * *", want: time.Date(2026, 3, 4, 10, 15, 0, 0, time.UTC)},
+		{spec: "5/20 * * * *", want: time.Date(2026, 3, 4, 10, 25, 0, 0, time.UTC)},
+		{spec: "0 9-17 * * *", want: time.Date(2026, 3, 4, 11, 0, 0, 0, time.UTC)},
+		{spec: "30 6,18 * * *", want: time.Date(2026, 3, 4, 18, 30, 0, 0, time.UTC)},
+		{spec: "0 0 * * mon-fri", want: time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 12 * * 7", want: time.Date(2026, 3, 8, 12, 0, 0, 0, time.UTC)},
+		{spec: "0 0 1 jan *", want: time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 29 2 *", want: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: the 10th or any Friday, whichever comes first.
+		{spec: "0 0 10 * fri", want: time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC)},
+		{spec: "@hourly", want: time.Date(2026, 3, 4, 11, 0, 0, 0, time.UTC)},
+		{spec: "@monthly", want: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			// Arrange
+			expr, err := cron.Parse(tt.spec)
+			require.NoError(t, err)
+
+			// Act
+			next := expr.Next(from)
+
+			// Assert
+			assert.Equal(t, tt.want, next)
+		})
+	}
+}
+
+func TestExpression_Next_Never(t *testing.T) {
+	expr, err := cron.Parse("0 0 30 2 *")
+	require.NoError(t, err)
+
+	assert.True(t, expr.Next(time.Now()).IsZero())
+}
+
+func TestExpression_Next_Location(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	expr, err := cron.Parse("0 8 * * *")
+	require.NoError(t, err)
+
+	next := expr.Next(time.Date(2026, 3, 4, 9, 0, 0, 0, loc))
+
+	assert.Equal(t, time.Date(2026, 3, 5, 8, 0, 0, 0, loc), next)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"10-5 * * * *",
+		"* * * foo *",
+		"@often",
+	} {
+		t.Run(spec, func(t *testing.T) {
+			_, err := cron.Parse(spec)
+
+			assert.ErrorIs(t, err, cron.ErrInvalidExpression)
+		})
+	}
+}
+
+func TestEvery_Next(t *testing.T) {
+	from := time.Date(2026, 3, 4, 10, 7, 30, 0, time.UTC)
+
+	assert.Equal(t, from.Add(15*time.Minute), cron.Every(15*time.Minute).Next(from))
+}
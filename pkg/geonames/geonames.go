@@ -0,0 +1,159 @@
+// Package geonames reads the tab-separated dumps published at https://download.geonames.org/export/dump/,
+// such as cities500.txt and admin1CodesASCII.txt.
+package geonames
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidRecord = errors.New("geonames: invalid record")
+
+// columns is the number of fields of a geoname record.
+const columns = 19
+
+// FeatureClassPopulatedPlace is the feature class of cities, towns and villages.
+const FeatureClassPopulatedPlace = "P"
+
+// Place is one row of a geoname dump. Codes are kept as published; Country is an upper case
+// ISO 3166 code.
+type Place struct {
+	ID             int64
+	Name           string
+	ASCIIName      string
+	AlternateNames []string
+	Lat            float64
+	Lon            float64
+	FeatureClass   string
+	FeatureCode    string
+	Country        string
+	Admin1Code     string
+	Admin2Code     string
+	Population     int64
+	Timezone       string
+}
+
+// Admin1Key returns the key admin1CodesASCII.txt indexes the place's first-level region by, e.g. "GB.ENG".
+func (p *Place) Admin1Key() string {
+	return p.Country + "." + p.Admin1Code
+}
+
+type Reader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func NewReader(r io.Reader) *Reader {
+	scanner := bufio.NewScanner(r)
+	// Alternate names of large cities run to tens of kilobytes.
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	return &Reader{scanner: scanner}
+}
+
+// Read returns the next place, or io.EOF once the dump is exhausted. Blank lines and
+// #-comments are skipped.
+func (r *Reader) Read() (*Place, error) {
+	for r.scanner.Scan() {
+		r.line++
+
+		line := r.scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		place, err := parsePlace(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", r.line, err)
+		}
+
+		return place, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, io.EOF
+}
+
+func parsePlace(line string) (*Place, error) {
+	fields := strings.Split(line, "\t")
+	if len(fields) != columns {
+		return nil, fmt.Errorf("%w: %d fields, want %d", ErrInvalidRecord, len(fields), columns)
+	}
+
+	id, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: geonameid %q", ErrInvalidRecord, fields[0])
+	}
+
+	lat, err := strconv.ParseFloat(fields[4], 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: latitude %q", ErrInvalidRecord, fields[4])
+	}
+
+	lon, err := strconv.ParseFloat(fields[5], 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: longitude %q", ErrInvalidRecord, fields[5])
+	}
+
+	var population int64
+	if fields[14] != "" {
+		if population, err = strconv.ParseInt(fields[14], 10, 64); err != nil {
+			return nil, fmt.Errorf("%w: population %q", ErrInvalidRecord, fields[14])
+		}
+	}
+
+	var alternates []string
+	if fields[3] != "" {
+		alternates = strings.Split(fields[3], ",")
+	}
+
+	return &Place{
+		ID:             id,
+		Name:           fields[1],
+		ASCIIName:      fields[2],
+		AlternateNames: alternates,
+		Lat:            lat,
+		Lon:            lon,
+		FeatureClass:   fields[6],
+		FeatureCode:    fields[7],
+		Country:        fields[8],
+		Admin1Code:     fields[10],
+		Admin2Code:     fields[11],
+		Population:     population,
+		Timezone:       fields[17],
+	}, nil
+}
+
+// ReadAdmin1Names reads admin1CodesASCII.txt into a map from keys such as "GB.ENG" to region
+// names such as "England".
+func ReadAdmin1Names(r io.Reader) (map[string]string, error) {
+	names := make(map[string]string)
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Split(text, "\t")
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("line %d: %w: %d fields, want at least 2", line, ErrInvalidRecord, len(fields))
+		}
+
+		names[fields[0]] = fields[1]
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
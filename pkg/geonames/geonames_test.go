@@ -0,0 +1,90 @@
+package geonames_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xoltawn/weatherhub/pkg/geonames"
+)
+
+func TestReader_Read(t *testing.T) {
+	t.Run("dump", func(t *testing.T) {
+		// Arrange
+		f, err := os.Open("testdata/cities.txt")
+		require.NoError(t, err)
+		defer f.Close()
+
+		r := geonames.NewReader(f)
+
+		// Act
+		var places []*geonames.Place
+		for {
+			place, err := r.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			require.NoError(t, err)
+			places = append(places, place)
+		}
+
+		// Assert
+		require.Len(t, places, 5)
+		assert.Equal(t, &geonames.Place{
+			ID:             2643743,
+			Name:           "London",
+			ASCIIName:      "London",
+			AlternateNames: []string{"Gorad Londan", "LON", "Londen", "Londinium", "Londra", "Londres", "London", "Лондон", "ロンドン"},
+			Lat:            51.50853,
+			Lon:            -0.12574,
+			FeatureClass:   geonames.FeatureClassPopulatedPlace,
+			FeatureCode:    "PPLC",
+			Country:        "GB",
+			Admin1Code:     "ENG",
+			Admin2Code:     "GLA",
+			Population:     8961989,
+			Timezone:       "Europe/London",
+		}, places[0])
+		assert.Equal(t, "CA.08", places[1].Admin1Key())
+		assert.Nil(t, places[3].AlternateNames)
+		assert.Zero(t, places[4].Population)
+	})
+
+	t.Run("wrong-field-count", func(t *testing.T) {
+		r := geonames.NewReader(strings.NewReader("# header\n2643743\tLondon\n"))
+
+		_, err := r.Read()
+
+		assert.ErrorIs(t, err, geonames.ErrInvalidRecord)
+		assert.ErrorContains(t, err, "line 2")
+	})
+
+	t.Run("invalid-coordinates", func(t *testing.T) {
+		line := strings.Join([]string{"1", "X", "X", "", "north", "0", "P", "PPL", "GB", "", "", "", "", "", "0", "", "", "", ""}, "\t")
+		r := geonames.NewReader(strings.NewReader(line))
+
+		_, err := r.Read()
+
+		assert.ErrorIs(t, err, geonames.ErrInvalidRecord)
+		assert.ErrorContains(t, err, "latitude")
+	})
+}
+
+func TestReadAdmin1Names(t *testing.T) {
+	f, err := os.Open("testdata/admin1CodesASCII.txt")
+	require.NoError(t, err)
+	defer f.Close()
+
+	names, err := geonames.ReadAdmin1Names(f)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"GB.ENG": "England",
+		"GB.NIR": "Northern Ireland",
+		"CA.08":  "Ontario",
+	}, names)
+}
@@ -0,0 +1,94 @@
+package httputil
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/pkg/errutil"
+)
+
+// NewClient returns a client with its own transport so that providers don't share connection pools.
+// A nil proxy keeps the proxy settings from the environment.
+func NewClient(timeout time.Duration, proxy *url.URL) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if proxy != nil {
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+	}
+}
+
+// GetJSON performs a GET request bound to ctx and decodes a 200 response into out.
+// Transport failures and other statuses are returned as *domain.UpstreamError,
+// undecodable bodies as domain.ErrThirdParty.
+func GetJSON(ctx context.Context, client *http.Client, rawURL string, header http.Header, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return errutil.Wrap(domain.ErrInternal, err.Error())
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return &domain.UpstreamError{Err: StripURL(err)}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &domain.UpstreamError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return errutil.Wrap(domain.ErrThirdParty, err.Error())
+	}
+
+	return nil
+}
+
+// ParseRetryAfter parses a Retry-After header given either in seconds or as an HTTP date.
+// It returns 0 when the header is empty, malformed or in the past.
+func ParseRetryAfter(header string, now time.Time) time.Duration {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if at, err := http.ParseTime(header); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+
+	return 0
+}
+
+// StripURL drops the *url.Error wrapper returned by http.Client, whose message repeats
+// the request URL and therefore any credentials in its query string.
+func StripURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+
+	return err
+}
@@ -0,0 +1,83 @@
+package httputil
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Defaults shared by the weather providers.
+const (
+	DefaultTimeout   = 10 * time.Second
+	DefaultUserAgent = "weatherhub/1.0"
+)
+
+// Options configure how a provider calls its upstream API.
+type Options struct {
+	HTTPClient *http.Client
+	Timeout    time.Duration
+	Proxy      *url.URL
+	UserAgent  string
+}
+
+// Option changes one of the Options of a provider.
+type Option func(*Options)
+
+// WithHTTPClient makes the provider use the given client as is. Timeout and proxy options are ignored.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *Options) {
+		o.HTTPClient = client
+	}
+}
+
+// WithTimeout bounds the total time of a single upstream call. Defaults to DefaultTimeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *Options) {
+		o.Timeout = timeout
+	}
+}
+
+// WithProxy routes upstream calls through the given proxy instead of the one from the environment.
+func WithProxy(proxy *url.URL) Option {
+	return func(o *Options) {
+		o.Proxy = proxy
+	}
+}
+
+// WithUserAgent sets the User-Agent sent upstream. Defaults to DefaultUserAgent.
+func WithUserAgent(userAgent string) Option {
+	return func(o *Options) {
+		o.UserAgent = userAgent
+	}
+}
+
+// NewOptions applies opts over the defaults.
+func NewOptions(opts ...Option) Options {
+	o := Options{
+		Timeout:   DefaultTimeout,
+		UserAgent: DefaultUserAgent,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// Client returns the client set with WithHTTPClient, or else a new one with the timeout and proxy.
+func (o Options) Client() *http.Client {
+	if o.HTTPClient != nil {
+		return o.HTTPClient
+	}
+
+	return NewClient(o.Timeout, o.Proxy)
+}
+
+// JSONHeader returns the headers of a request for JSON sent as userAgent.
+func JSONHeader(userAgent string) http.Header {
+	header := http.Header{}
+	header.Set("User-Agent", userAgent)
+	header.Set("Accept", "application/json")
+
+	return header
+}
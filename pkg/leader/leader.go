@@ -0,0 +1,178 @@
+// Package leader elects one leader among the replicas sharing a Redis server, so that
+// background work runs once rather than on every replica.
+//
+// The leader holds a lease: a Redis key naming it, with a TTL that it renews well before expiry.
+// Each new lease is issued a fencing token from a counter that only grows, so a replica that
+// stalled past its lease and resumed can be told apart from the current leader by the systems it
+// writes to. A leader that shuts down releases its lease and announces it, and the other replicas
+// take over right away instead of waiting for the TTL.
+package leader
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	DefaultLeaseTTL = 15 * time.Second
+	// releaseTimeout bounds the release on shutdown, which runs after the caller's context is done.
+	releaseTimeout = 2 * time.Second
+)
+
+type Config struct {
+	// Key names the lease; replicas using the same key compete for the same leadership.
+	Key string
+	// ID identifies this replica. It defaults to the host name with a random suffix.
+	ID string
+	// LeaseTTL is how long a lease lasts without renewal, and so how long leadership can go
+	// unclaimed after a leader crashes. It defaults to DefaultLeaseTTL.
+	LeaseTTL time.Duration
+	// RenewInterval is how often the leader renews and followers try to acquire the lease. It
+	// defaults to a third of LeaseTTL.
+	RenewInterval time.Duration
+	// OnChange, if set, is called whenever this replica gains or loses leadership.
+	OnChange func(leading bool, token int64)
+}
+
+type Elector struct {
+	store leaseStore
+	cfg   Config
+
+	mu        sync.Mutex
+	token     int64
+	expiresAt time.Time
+}
+
+func New(rdb redis.UniversalClient, cfg Config) *Elector {
+	return newElector(&redisStore{rdb: rdb, key: cfg.Key}, cfg)
+}
+
+func newElector(store leaseStore, cfg Config) *Elector {
+	if cfg.ID == "" {
+		cfg.ID = defaultID()
+	}
+	if cfg.LeaseTTL <= 0 {
+		cfg.LeaseTTL = DefaultLeaseTTL
+	}
+	if cfg.RenewInterval <= 0 {
+		cfg.RenewInterval = cfg.LeaseTTL / 3
+	}
+
+	return &Elector{store: store, cfg: cfg}
+}
+
+func (e *Elector) ID() string {
+	return e.cfg.ID
+}
+
+// IsLeader reports whether this replica holds an unexpired lease. The expiry is reckoned from
+// before the lease was last acquired or renewed, so it never outlasts the lease in Redis.
+func (e *Elector) IsLeader() bool {
+	_, ok := e.Token()
+	return ok
+}
+
+// Token returns the fencing token of the lease this replica holds, if any. Writes made on behalf
+// of the leader can carry it, for their targets to reject tokens older than one they've seen.
+func (e *Elector) Token() (int64, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.token == 0 || !time.Now().Before(e.expiresAt) {
+		return 0, false
+	}
+
+	return e.token, true
+}
+
+// Run campaigns for leadership until ctx is done, then releases the lease if it holds it.
+func (e *Elector) Run(ctx context.Context) {
+	released, stop := e.store.subscribe(ctx)
+	defer stop()
+
+	ticker := time.NewTicker(e.cfg.RenewInterval)
+	defer ticker.Stop()
+
+	for {
+		e.campaign(ctx)
+
+		select {
+		case <-ctx.Done():
+			releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), releaseTimeout)
+			defer cancel()
+			e.resign(releaseCtx)
+			return
+		case <-ticker.C:
+		case <-released:
+		}
+	}
+}
+
+// campaign acquires the lease, or renews it if it's already held.
+func (e *Elector) campaign(ctx context.Context) {
+	e.mu.Lock()
+	held := e.token
+	e.mu.Unlock()
+
+	start := time.Now()
+	token, err := e.store.acquire(ctx, e.cfg.ID, held, e.cfg.LeaseTTL)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("leader: failed to acquire %s: %v", e.cfg.Key, err)
+		}
+		// Leadership lapses on its own when the lease can't be renewed in time.
+		return
+	}
+
+	e.set(token, start.Add(e.cfg.LeaseTTL))
+}
+
+func (e *Elector) resign(ctx context.Context) {
+	e.mu.Lock()
+	token := e.token
+	e.mu.Unlock()
+
+	if token == 0 {
+		return
+	}
+
+	if err := e.store.release(ctx, e.cfg.ID, token); err != nil {
+		log.Printf("leader: failed to release %s: %v", e.cfg.Key, err)
+	}
+
+	e.set(0, time.Time{})
+}
+
+func (e *Elector) set(token int64, expiresAt time.Time) {
+	e.mu.Lock()
+	changed := token != e.token
+	e.token = token
+	e.expiresAt = expiresAt
+	e.mu.Unlock()
+
+	if changed {
+		log.Printf("leader: %s leading %s: %t (token %d)", e.cfg.ID, e.cfg.Key, token != 0, token)
+		if e.cfg.OnChange != nil {
+			e.cfg.OnChange(token != 0, token)
+		}
+	}
+}
+
+func defaultID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "replica"
+	}
+
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+
+	return host + "-" + hex.EncodeToString(suffix)
+}
@@ -0,0 +1,176 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memStore is an in-memory leaseStore with the semantics of the Redis scripts.
+type memStore struct {
+	mu        sync.Mutex
+	holder    string
+	token     int64
+	expiresAt time.Time
+	counter   int64
+	err       error
+	listeners []chan struct{}
+}
+
+func (s *memStore) acquire(ctx context.Context, id string, token int64, ttl time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return 0, s.err
+	}
+
+	now := time.Now()
+	if s.holder != "" && now.Before(s.expiresAt) {
+		if s.holder == id && s.token == token {
+			s.expiresAt = now.Add(ttl)
+			return token, nil
+		}
+		return 0, nil
+	}
+
+	s.counter++
+	s.holder, s.token, s.expiresAt = id, s.counter, now.Add(ttl)
+
+	return s.counter, nil
+}
+
+func (s *memStore) release(ctx context.Context, id string, token int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.holder == id && s.token == token {
+		s.holder = ""
+		for _, l := range s.listeners {
+			select {
+			case l <- struct{}{}:
+			default:
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *memStore) subscribe(ctx context.Context) (<-chan struct{}, func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l := make(chan struct{}, 1)
+	s.listeners = append(s.listeners, l)
+
+	return l, func() {}
+}
+
+func (s *memStore) setErr(err error) {
+	s.mu.Lock()
+	s.err = err
+	s.mu.Unlock()
+}
+
+func TestElector_Campaign(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("first-replica-leads", func(t *testing.T) {
+		// Arrange
+		store := &memStore{}
+		a := newElector(store, Config{Key: "jobs", ID: "a", LeaseTTL: time.Second})
+		b := newElector(store, Config{Key: "jobs", ID: "b", LeaseTTL: time.Second})
+
+		// Act
+		a.campaign(ctx)
+		b.campaign(ctx)
+
+		// Assert
+		token, ok := a.Token()
+		assert.True(t, ok)
+		assert.EqualValues(t, 1, token)
+		assert.False(t, b.IsLeader())
+	})
+
+	t.Run("renewal-keeps-the-token", func(t *testing.T) {
+		store := &memStore{}
+		a := newElector(store, Config{Key: "jobs", ID: "a", LeaseTTL: time.Second})
+
+		a.campaign(ctx)
+		a.campaign(ctx)
+
+		token, ok := a.Token()
+		assert.True(t, ok)
+		assert.EqualValues(t, 1, token)
+	})
+
+	t.Run("leadership-lapses-without-renewal", func(t *testing.T) {
+		store := &memStore{}
+		a := newElector(store, Config{Key: "jobs", ID: "a", LeaseTTL: 30 * time.Millisecond})
+		b := newElector(store, Config{Key: "jobs", ID: "b", LeaseTTL: 30 * time.Millisecond})
+
+		a.campaign(ctx)
+		store.setErr(errors.New("connection refused"))
+		a.campaign(ctx)
+		time.Sleep(40 * time.Millisecond)
+
+		assert.False(t, a.IsLeader())
+
+		store.setErr(nil)
+		b.campaign(ctx)
+		a.campaign(ctx)
+
+		// The new leader's token is higher, and the old leader learns it lost.
+		token, ok := b.Token()
+		assert.True(t, ok)
+		assert.EqualValues(t, 2, token)
+		assert.False(t, a.IsLeader())
+	})
+}
+
+func TestElector_Run_HandsOverOnShutdown(t *testing.T) {
+	// Arrange
+	store := &memStore{}
+
+	var mu sync.Mutex
+	var changes []bool
+	a := newElector(store, Config{Key: "jobs", ID: "a", LeaseTTL: time.Minute})
+	b := newElector(store, Config{Key: "jobs", ID: "b", LeaseTTL: time.Minute, OnChange: func(leading bool, token int64) {
+		mu.Lock()
+		changes = append(changes, leading)
+		mu.Unlock()
+	}})
+
+	ctxA, stopA := context.WithCancel(context.Background())
+	doneA := make(chan struct{})
+	go func() {
+		a.Run(ctxA)
+		close(doneA)
+	}()
+	require.Eventually(t, a.IsLeader, time.Second, time.Millisecond)
+
+	ctxB, stopB := context.WithCancel(context.Background())
+	defer stopB()
+	go b.Run(ctxB)
+
+	// Act
+	stopA()
+	<-doneA
+
+	// Assert: b takes over on the release announcement, long before the lease would expire.
+	require.Eventually(t, b.IsLeader, time.Second, time.Millisecond)
+	assert.False(t, a.IsLeader())
+
+	token, _ := b.Token()
+	assert.EqualValues(t, 2, token)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []bool{true}, changes)
+}
@@ -0,0 +1,76 @@
+package leader
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// leaseStore keeps the lease. It's an interface so the election can be tested without Redis.
+type leaseStore interface {
+	// acquire renews the lease if id holds it with token, takes it with a new token if it's
+	// free, and returns the token id holds afterwards: 0 when another replica holds the lease.
+	acquire(ctx context.Context, id string, token int64, ttl time.Duration) (int64, error)
+	// release frees the lease if id holds it with token, and announces it.
+	release(ctx context.Context, id string, token int64) error
+	// subscribe notifies of released leases until stop is called.
+	subscribe(ctx context.Context) (released <-chan struct{}, stop func())
+}
+
+// The lease value is "<id>:<token>". KEYS[1] is the lease and KEYS[2] the token counter.
+var acquireScript = redis.NewScript(`
+local current = redis.call('GET', KEYS[1])
+if current then
+	if current == ARGV[1] .. ':' .. ARGV[2] then
+		redis.call('PEXPIRE', KEYS[1], ARGV[3])
+		return tonumber(ARGV[2])
+	end
+	return 0
+end
+local token = redis.call('INCR', KEYS[2])
+redis.call('SET', KEYS[1], ARGV[1] .. ':' .. token, 'PX', ARGV[3])
+return token
+`)
+
+// KEYS[1] is the lease and KEYS[2] the release channel.
+var releaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	redis.call('DEL', KEYS[1])
+	redis.call('PUBLISH', KEYS[2], ARGV[1])
+	return 1
+end
+return 0
+`)
+
+type redisStore struct {
+	rdb redis.UniversalClient
+	key string
+}
+
+func (s *redisStore) acquire(ctx context.Context, id string, token int64, ttl time.Duration) (int64, error) {
+	keys := []string{s.key, s.key + ":token"}
+	return acquireScript.Run(ctx, s.rdb, keys, id, strconv.FormatInt(token, 10), ttl.Milliseconds()).Int64()
+}
+
+func (s *redisStore) release(ctx context.Context, id string, token int64) error {
+	keys := []string{s.key, s.key + ":released"}
+	return releaseScript.Run(ctx, s.rdb, keys, id+":"+strconv.FormatInt(token, 10)).Err()
+}
+
+func (s *redisStore) subscribe(ctx context.Context) (<-chan struct{}, func()) {
+	sub := s.rdb.Subscribe(ctx, s.key+":released")
+	released := make(chan struct{}, 1)
+
+	go func() {
+		for range sub.Channel() {
+			select {
+			case released <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	return released, func() { sub.Close() }
+}
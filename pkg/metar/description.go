@@ -0,0 +1,135 @@
+package metar
+
+import "strings"
+
+var descriptorWords = map[string]string{
+	"MI": "shallow",
+	"PR": "partial",
+	"BC": "patches of",
+	"DR": "low drifting",
+	"BL": "blowing",
+	"FZ": "freezing",
+}
+
+var precipitationWords = map[string]string{
+	"DZ": "drizzle",
+	"RA": "rain",
+	"SN": "snow",
+	"SG": "snow grains",
+	"IC": "ice crystals",
+	"PL": "ice pellets",
+	"GR": "hail",
+	"GS": "small hail",
+	"UP": "unknown precipitation",
+}
+
+var obscurationWords = map[string]string{
+	"BR": "mist",
+	"FG": "fog",
+	"FU": "smoke",
+	"VA": "volcanic ash",
+	"DU": "dust",
+	"SA": "sand",
+	"HZ": "haze",
+	"PY": "spray",
+}
+
+var otherWords = map[string]string{
+	"PO":  "dust whirls",
+	"SQ":  "squalls",
+	"FC":  "funnel cloud",
+	"+FC": "tornado",
+	"SS":  "sandstorm",
+	"DS":  "duststorm",
+}
+
+// cloudWords follow OpenWeatherMap's wording so descriptions look alike across providers.
+var cloudWords = map[string]string{
+	"FEW": "few clouds",
+	"SCT": "scattered clouds",
+	"BKN": "broken clouds",
+	"OVC": "overcast clouds",
+	"VV":  "sky obscured",
+}
+
+var cloudRank = map[string]int{"FEW": 1, "SCT": 2, "BKN": 3, "OVC": 4, "VV": 5}
+
+// Describe renders the phenomenon in plain English, e.g. "-SHRA" as "light rain showers".
+func (p Phenomenon) Describe() string {
+	var words []string
+
+	switch p.Intensity {
+	case "-":
+		words = append(words, "light")
+	case "+":
+		words = append(words, "heavy")
+	}
+
+	if desc, ok := descriptorWords[p.Descriptor]; ok {
+		words = append(words, desc)
+	}
+
+	var precip []string
+	for _, code := range p.Precipitation {
+		precip = append(precip, precipitationWords[code])
+	}
+	if len(precip) > 0 {
+		words = append(words, strings.Join(precip, " and "))
+	}
+
+	if desc, ok := obscurationWords[p.Obscuration]; ok {
+		words = append(words, desc)
+	}
+	if desc, ok := otherWords[p.Intensity+p.Other]; ok && p.Intensity == "+" {
+		words = []string{desc}
+	} else if desc, ok := otherWords[p.Other]; ok {
+		words = append(words, desc)
+	}
+
+	if p.Descriptor == "SH" {
+		words = append(words, "showers")
+	}
+
+	desc := strings.Join(words, " ")
+	if p.Descriptor == "TS" {
+		if desc == "" {
+			desc = "thunderstorm"
+		} else {
+			desc = "thunderstorm with " + desc
+		}
+	}
+
+	if p.Intensity == "VC" {
+		desc += " in the vicinity"
+	}
+
+	return desc
+}
+
+// Description summarizes the report: present weather when there is any, the most significant
+// cloud layer otherwise.
+func (m *METAR) Description() string {
+	if len(m.Weather) > 0 {
+		descs := make([]string, 0, len(m.Weather))
+		for _, p := range m.Weather {
+			descs = append(descs, p.Describe())
+		}
+		return strings.Join(descs, ", ")
+	}
+
+	cover := ""
+	for _, layer := range m.Clouds {
+		if cloudRank[layer.Cover] > cloudRank[cover] {
+			cover = layer.Cover
+		}
+	}
+	if desc, ok := cloudWords[cover]; ok {
+		return desc
+	}
+
+	if len(m.Clouds) > 0 || (m.Visibility != nil && m.Visibility.CAVOK) {
+		return "clear sky"
+	}
+
+	return "unknown"
+}
@@ -0,0 +1,416 @@
+// Package metar parses METAR and SPECI aviation weather reports and provides a
+// domain.WeatherProvider based on the nearest reporting station.
+package metar
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xoltawn/weatherhub/pkg/meteo"
+)
+
+var ErrInvalidReport = errors.New("metar: invalid report")
+
+type METAR struct {
+	Raw string
+	// Type is METAR or SPECI.
+	Type    string
+	Station string
+	// Day, Hour and Minute are the UTC observation time; use ObservedAt to anchor them to a month.
+	Day    int
+	Hour   int
+	Minute int
+	// Nil is set for NIL reports, which carry no observation.
+	Nil        bool
+	Auto       bool
+	Corrected  bool
+	Wind       *Wind
+	Visibility *Visibility
+	RVR        []RunwayVisualRange
+	Weather    []Phenomenon
+	Clouds     []CloudLayer
+	// Temperature and Dewpoint are in °C. They come from the remarks T group when present,
+	// which has tenths of a degree, and from the body otherwise.
+	Temperature *float64
+	Dewpoint    *float64
+	Altimeter   *Altimeter
+	// Trend holds the raw TAF-style trend forecast, e.g. "NOSIG" or "BECMG 2000 9999".
+	Trend   string
+	Remarks string
+	// Unparsed holds body groups the parser didn't recognize.
+	Unparsed []string
+}
+
+type Wind struct {
+	// Direction is in degrees true; it's meaningless when Variable is set.
+	Direction int
+	Variable  bool
+	Speed     int
+	Gust      int
+	// Unit is KT, MPS or KMH.
+	Unit string
+	// VariableFrom and VariableTo give the range of a varying direction, as in 180V240.
+	VariableFrom int
+	VariableTo   int
+}
+
+// SpeedMS returns the mean wind speed in m/s.
+func (w *Wind) SpeedMS() float64 {
+	return toMS(float64(w.Speed), w.Unit)
+}
+
+// GustMS returns the gust speed in m/s, or 0 without gusts.
+func (w *Wind) GustMS() float64 {
+	return toMS(float64(w.Gust), w.Unit)
+}
+
+type Visibility struct {
+	Distance float64
+	// Unit is SM for statute miles or M for meters.
+	Unit        string
+	LessThan    bool
+	GreaterThan bool
+	CAVOK       bool
+}
+
+// Meters returns the visibility in meters. CAVOK and 9999 both mean 10 km or more.
+func (v *Visibility) Meters() float64 {
+	if v.CAVOK {
+		return 10000
+	}
+	if v.Unit == "SM" {
+		return meteo.StatuteMilesToMeters(v.Distance)
+	}
+
+	return v.Distance
+}
+
+type RunwayVisualRange struct {
+	Runway string
+	// Min and Max are equal unless the range varies.
+	Min  int
+	Max  int
+	Unit string
+	// Trend is U (up), D (down), N (no change) or empty.
+	Trend string
+}
+
+type Phenomenon struct {
+	// Intensity is "-" (light), "+" (heavy), "VC" (in the vicinity) or empty for moderate.
+	Intensity     string
+	Descriptor    string
+	Precipitation []string
+	Obscuration   string
+	Other         string
+	Raw           string
+}
+
+type CloudLayer struct {
+	// Cover is FEW, SCT, BKN, OVC or VV for vertical visibility; SKC, CLR, NSC and NCD report no clouds.
+	Cover string
+	// Height is the base in feet above ground, or -1 when not reported.
+	Height int
+	// Type is CB or TCU for convective clouds.
+	Type string
+}
+
+type Altimeter struct {
+	Value float64
+	// Unit is inHg or hPa.
+	Unit string
+}
+
+// HPa returns the altimeter setting in hectopascals.
+func (a *Altimeter) HPa() float64 {
+	if a.Unit == "inHg" {
+		return meteo.InHgToHPa(a.Value)
+	}
+
+	return a.Value
+}
+
+// ObservedAt returns the observation time, resolved to the latest matching day at or before ref.
+func (m *METAR) ObservedAt(ref time.Time) time.Time {
+	ref = ref.UTC()
+
+	for monthOffset := 0; monthOffset < 3; monthOffset++ {
+		year, month, _ := ref.AddDate(0, -monthOffset, 0).Date()
+		t := time.Date(year, month, m.Day, m.Hour, m.Minute, 0, 0, time.UTC)
+		// time.Date normalizes day 31 in a 30-day month into the next month.
+		if t.Day() == m.Day && !t.After(ref.Add(time.Hour)) {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+var (
+	stationRe    = regexp.MustCompile(`^[A-Z][A-Z0-9]{3}$`)
+	timeRe       = regexp.MustCompile(`^(\d{2})(\d{2})(\d{2})Z$`)
+	windRe       = regexp.MustCompile(`^(\d{3}|VRB)(\d{2,3})(?:G(\d{2,3}))?(KT|MPS|KMH)$`)
+	windVarRe    = regexp.MustCompile(`^(\d{3})V(\d{3})$`)
+	visMetersRe  = regexp.MustCompile(`^(\d{4})(NDV|N|NE|E|SE|S|SW|W|NW)?$`)
+	visMilesRe   = regexp.MustCompile(`^([MP])?(\d+)SM$`)
+	visFracRe    = regexp.MustCompile(`^(M)?(\d+)/(\d+)SM$`)
+	wholeRe      = regexp.MustCompile(`^\d$`)
+	rvrRe        = regexp.MustCompile(`^R(\d{2}[LCR]?)/([MP]?\d{4})(?:V([MP]?\d{4}))?(FT)?/?([UDN])?$`)
+	weatherRe    = regexp.MustCompile(`^(-|\+|VC)?(MI|PR|BC|DR|BL|SH|TS|FZ)?((?:DZ|RA|SN|SG|IC|PL|GR|GS|UP)*)(BR|FG|FU|VA|DU|SA|HZ|PY)?(PO|SQ|\+?FC|SS|DS)?$`)
+	cloudRe      = regexp.MustCompile(`^(FEW|SCT|BKN|OVC|VV)(\d{3}|///)(CB|TCU|///)?$`)
+	tempRe       = regexp.MustCompile(`^(M?\d{2}|//)/(M?\d{2}|//)?$`)
+	altimeterRe  = regexp.MustCompile(`^([AQ])(\d{4})$`)
+	remarkTempRe = regexp.MustCompile(`^T([01])(\d{3})([01])(\d{3})$`)
+)
+
+var noCloudCodes = map[string]bool{"SKC": true, "CLR": true, "NSC": true, "NCD": true}
+
+var trendCodes = map[string]bool{"NOSIG": true, "BECMG": true, "TEMPO": true}
+
+// Parse parses a single METAR or SPECI report. Groups that aren't understood are collected
+// in Unparsed instead of failing the whole report; only a missing station or time is an error.
+func Parse(raw string) (*METAR, error) {
+	raw = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(raw), "="))
+	tokens := strings.Fields(raw)
+
+	m := &METAR{Raw: raw, Type: "METAR"}
+
+	i := 0
+	if i < len(tokens) && (tokens[i] == "METAR" || tokens[i] == "SPECI") {
+		m.Type = tokens[i]
+		i++
+	}
+	if i < len(tokens) && tokens[i] == "COR" {
+		m.Corrected = true
+		i++
+	}
+
+	if i >= len(tokens) || !stationRe.MatchString(tokens[i]) {
+		return nil, fmt.Errorf("%w: missing station identifier", ErrInvalidReport)
+	}
+	m.Station = tokens[i]
+	i++
+
+	if i >= len(tokens) {
+		return nil, fmt.Errorf("%w: missing observation time", ErrInvalidReport)
+	}
+	match := timeRe.FindStringSubmatch(tokens[i])
+	if match == nil {
+		return nil, fmt.Errorf("%w: malformed observation time %q", ErrInvalidReport, tokens[i])
+	}
+	m.Day, m.Hour, m.Minute = atoi(match[1]), atoi(match[2]), atoi(match[3])
+	if m.Day < 1 || m.Day > 31 || m.Hour > 23 || m.Minute > 59 {
+		return nil, fmt.Errorf("%w: malformed observation time %q", ErrInvalidReport, tokens[i])
+	}
+	i++
+
+	var bodyTemp, bodyDew *float64
+
+	for ; i < len(tokens); i++ {
+		tok := tokens[i]
+
+		switch {
+		case tok == "RMK":
+			m.Remarks = strings.Join(tokens[i+1:], " ")
+			i = len(tokens)
+		case trendCodes[tok] || strings.HasPrefix(tok, "FM") && len(tok) == 6:
+			end := len(tokens)
+			for j := i; j < len(tokens); j++ {
+				if tokens[j] == "RMK" {
+					end = j
+					break
+				}
+			}
+			m.Trend = strings.Join(tokens[i:end], " ")
+			i = end - 1
+		case tok == "NIL":
+			m.Nil = true
+		case tok == "AUTO":
+			m.Auto = true
+		case tok == "COR":
+			m.Corrected = true
+		case tok == "CAVOK":
+			m.Visibility = &Visibility{Distance: 10000, Unit: "M", CAVOK: true}
+		case tok == "NSW":
+			// "No significant weather" only appears in trends; nothing to record.
+		case noCloudCodes[tok]:
+			m.Clouds = append(m.Clouds, CloudLayer{Cover: tok, Height: -1})
+		case windRe.MatchString(tok):
+			m.Wind = parseWind(windRe.FindStringSubmatch(tok))
+		case windVarRe.MatchString(tok) && m.Wind != nil:
+			match := windVarRe.FindStringSubmatch(tok)
+			m.Wind.VariableFrom, m.Wind.VariableTo = atoi(match[1]), atoi(match[2])
+		case wholeRe.MatchString(tok) && i+1 < len(tokens) && visFracRe.MatchString(tokens[i+1]):
+			frac := visFracRe.FindStringSubmatch(tokens[i+1])
+			m.Visibility = &Visibility{
+				Distance: float64(atoi(tok)) + float64(atoi(frac[2]))/float64(atoi(frac[3])),
+				Unit:     "SM",
+			}
+			i++
+		case visFracRe.MatchString(tok):
+			frac := visFracRe.FindStringSubmatch(tok)
+			m.Visibility = &Visibility{
+				Distance: float64(atoi(frac[2])) / float64(atoi(frac[3])),
+				Unit:     "SM",
+				LessThan: frac[1] == "M",
+			}
+		case visMilesRe.MatchString(tok):
+			match := visMilesRe.FindStringSubmatch(tok)
+			m.Visibility = &Visibility{
+				Distance:    float64(atoi(match[2])),
+				Unit:        "SM",
+				LessThan:    match[1] == "M",
+				GreaterThan: match[1] == "P",
+			}
+		case visMetersRe.MatchString(tok) && m.Visibility == nil:
+			match := visMetersRe.FindStringSubmatch(tok)
+			m.Visibility = &Visibility{
+				Distance:    float64(atoi(match[1])),
+				Unit:        "M",
+				GreaterThan: match[1] == "9999",
+			}
+		case rvrRe.MatchString(tok):
+			m.RVR = append(m.RVR, parseRVR(rvrRe.FindStringSubmatch(tok)))
+		case cloudRe.MatchString(tok):
+			m.Clouds = append(m.Clouds, parseCloud(cloudRe.FindStringSubmatch(tok)))
+		case tempRe.MatchString(tok):
+			match := tempRe.FindStringSubmatch(tok)
+			bodyTemp, bodyDew = parseTemp(match[1]), parseTemp(match[2])
+		case altimeterRe.MatchString(tok):
+			if m.Altimeter == nil {
+				m.Altimeter = parseAltimeter(altimeterRe.FindStringSubmatch(tok))
+			}
+		case tok != "" && weatherRe.MatchString(tok) && isPhenomenon(weatherRe.FindStringSubmatch(tok)):
+			m.Weather = append(m.Weather, parsePhenomenon(weatherRe.FindStringSubmatch(tok)))
+		default:
+			m.Unparsed = append(m.Unparsed, tok)
+		}
+	}
+
+	m.Temperature, m.Dewpoint = bodyTemp, bodyDew
+	for _, tok := range strings.Fields(m.Remarks) {
+		if match := remarkTempRe.FindStringSubmatch(tok); match != nil {
+			m.Temperature = tenths(match[1], match[2])
+			m.Dewpoint = tenths(match[3], match[4])
+			break
+		}
+	}
+
+	return m, nil
+}
+
+func parseWind(match []string) *Wind {
+	w := &Wind{
+		Speed: atoi(match[2]),
+		Unit:  match[4],
+	}
+	if match[1] == "VRB" {
+		w.Variable = true
+	} else {
+		w.Direction = atoi(match[1])
+	}
+	if match[3] != "" {
+		w.Gust = atoi(match[3])
+	}
+
+	return w
+}
+
+func parseRVR(match []string) RunwayVisualRange {
+	rvr := RunwayVisualRange{
+		Runway: match[1],
+		Min:    atoi(strings.TrimLeft(match[2], "MP")),
+		Unit:   "M",
+		Trend:  match[5],
+	}
+	rvr.Max = rvr.Min
+	if match[3] != "" {
+		rvr.Max = atoi(strings.TrimLeft(match[3], "MP"))
+	}
+	if match[4] == "FT" {
+		rvr.Unit = "FT"
+	}
+
+	return rvr
+}
+
+func parseCloud(match []string) CloudLayer {
+	layer := CloudLayer{Cover: match[1], Height: -1}
+	if match[2] != "///" {
+		layer.Height = atoi(match[2]) * 100
+	}
+	if match[3] != "///" {
+		layer.Type = match[3]
+	}
+
+	return layer
+}
+
+func parseTemp(s string) *float64 {
+	if s == "" || s == "//" {
+		return nil
+	}
+
+	v := float64(atoi(strings.TrimPrefix(s, "M")))
+	if strings.HasPrefix(s, "M") {
+		v = -v
+	}
+
+	return &v
+}
+
+func tenths(sign, digits string) *float64 {
+	v := float64(atoi(digits)) / 10
+	if sign == "1" {
+		v = -v
+	}
+
+	return &v
+}
+
+func parseAltimeter(match []string) *Altimeter {
+	if match[1] == "A" {
+		return &Altimeter{Value: float64(atoi(match[2])) / 100, Unit: "inHg"}
+	}
+
+	return &Altimeter{Value: float64(atoi(match[2])), Unit: "hPa"}
+}
+
+// isPhenomenon filters out regex matches that carry no actual weather, such as a bare intensity.
+func isPhenomenon(match []string) bool {
+	return match[3] != "" || match[4] != "" || match[5] != "" || match[2] == "TS" || match[2] == "SH"
+}
+
+func parsePhenomenon(match []string) Phenomenon {
+	p := Phenomenon{
+		Intensity:   match[1],
+		Descriptor:  match[2],
+		Obscuration: match[4],
+		Other:       match[5],
+		Raw:         match[0],
+	}
+	for j := 0; j+2 <= len(match[3]); j += 2 {
+		p.Precipitation = append(p.Precipitation, match[3][j:j+2])
+	}
+
+	return p
+}
+
+func toMS(speed float64, unit string) float64 {
+	switch unit {
+	case "KT":
+		return meteo.KnotsToMS(speed)
+	case "KMH":
+		return meteo.KMHToMS(speed)
+	default:
+		return speed
+	}
+}
+
+func atoi(s string) int {
+	v, _ := strconv.Atoi(s)
+	return v
+}
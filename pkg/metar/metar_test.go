@@ -0,0 +1,287 @@
+package metar_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xoltawn/weatherhub/pkg/metar"
+)
+
+func ptr(v float64) *float64 {
+	return &v
+}
+
+// TestParse runs the parser against a corpus of reports as published by real stations.
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		raw         string
+		station     string
+		wind        *metar.Wind
+		visMeters   float64
+		temperature *float64
+		dewpoint    *float64
+		altimeter   float64
+		description string
+		check       func(t *testing.T, m *metar.METAR)
+	}{
+		{
+			name:        "us-remarks-temperature",
+			raw:         "KJFK 181751Z 31012G20KT 10SM FEW050 SCT250 14/03 A3012 RMK AO2 SLP199 T01390028 10144 20089 58005",
+			station:     "KJFK",
+			wind:        &metar.Wind{Direction: 310, Speed: 12, Gust: 20, Unit: "KT"},
+			visMeters:   16093.44,
+			temperature: ptr(13.9),
+			dewpoint:    ptr(2.8),
+			altimeter:   1019.98,
+			description: "scattered clouds",
+			check: func(t *testing.T, m *metar.METAR) {
+				assert.Equal(t, "AO2 SLP199 T01390028 10144 20089 58005", m.Remarks)
+				assert.Empty(t, m.Unparsed)
+			},
+		},
+		{
+			name:        "us-fractional-visibility",
+			raw:         "KORD 181651Z 18008KT 1 1/2SM -RA BR OVC008 08/07 A2985 RMK AO2 P0002 T00830072",
+			station:     "KORD",
+			wind:        &metar.Wind{Direction: 180, Speed: 8, Unit: "KT"},
+			visMeters:   2414.02,
+			temperature: ptr(8.3),
+			dewpoint:    ptr(7.2),
+			altimeter:   1010.84,
+			description: "light rain, mist",
+		},
+		{
+			name:        "us-less-than-quarter-mile",
+			raw:         "KSFO 180956Z 00000KT M1/4SM FG VV001 11/11 A3001 RMK AO2 T01110106",
+			station:     "KSFO",
+			wind:        &metar.Wind{Speed: 0, Unit: "KT"},
+			visMeters:   402.34,
+			temperature: ptr(11.1),
+			dewpoint:    ptr(10.6),
+			altimeter:   1016.26,
+			description: "fog",
+			check: func(t *testing.T, m *metar.METAR) {
+				assert.True(t, m.Visibility.LessThan)
+				assert.Equal(t, []metar.CloudLayer{{Cover: "VV", Height: 100}}, m.Clouds)
+			},
+		},
+		{
+			name:        "auto-station-with-missing-groups",
+			raw:         "METAR LFPG 181730Z AUTO 22006KT 9999 ////// 12/09 Q1018 NOSIG",
+			station:     "LFPG",
+			wind:        &metar.Wind{Direction: 220, Speed: 6, Unit: "KT"},
+			visMeters:   9999,
+			temperature: ptr(12),
+			dewpoint:    ptr(9),
+			altimeter:   1018,
+			description: "unknown",
+			check: func(t *testing.T, m *metar.METAR) {
+				assert.True(t, m.Auto)
+				assert.Equal(t, "NOSIG", m.Trend)
+				assert.Equal(t, []string{"//////"}, m.Unparsed)
+			},
+		},
+		{
+			name:        "variable-wind-and-direction-range",
+			raw:         "EGLL 181650Z VRB03KT 180V240 CAVOK 17/08 Q1021 NOSIG",
+			station:     "EGLL",
+			wind:        &metar.Wind{Variable: true, Speed: 3, Unit: "KT", VariableFrom: 180, VariableTo: 240},
+			visMeters:   10000,
+			temperature: ptr(17),
+			dewpoint:    ptr(8),
+			altimeter:   1021,
+			description: "clear sky",
+			check: func(t *testing.T, m *metar.METAR) {
+				assert.True(t, m.Visibility.CAVOK)
+			},
+		},
+		{
+			name:        "meters-per-second-wind",
+			raw:         "UUEE 181700Z 27007MPS 9999 -SHRA BKN020CB 06/02 Q1009 R06L/290050 NOSIG",
+			station:     "UUEE",
+			wind:        &metar.Wind{Direction: 270, Speed: 7, Unit: "MPS"},
+			visMeters:   9999,
+			temperature: ptr(6),
+			dewpoint:    ptr(2),
+			altimeter:   1009,
+			description: "light rain showers",
+			check: func(t *testing.T, m *metar.METAR) {
+				assert.Equal(t, []metar.CloudLayer{{Cover: "BKN", Height: 2000, Type: "CB"}}, m.Clouds)
+				assert.Equal(t, []string{"R06L/290050"}, m.Unparsed)
+			},
+		},
+		{
+			name:        "negative-temperatures",
+			raw:         "CYFB 181700Z 32015G25KT 3SM -SN BLSN OVC012 M18/M22 A2968 RMK SC8 SLP062",
+			station:     "CYFB",
+			wind:        &metar.Wind{Direction: 320, Speed: 15, Gust: 25, Unit: "KT"},
+			visMeters:   4828.03,
+			temperature: ptr(-18),
+			dewpoint:    ptr(-22),
+			altimeter:   1005.08,
+			description: "light snow, blowing snow",
+		},
+		{
+			name:        "runway-visual-range",
+			raw:         "EDDF 180550Z 04003KT 0350 R25R/0500VP1500U R25L/0450N FG VV002 04/04 Q1025 BECMG 0800",
+			station:     "EDDF",
+			wind:        &metar.Wind{Direction: 40, Speed: 3, Unit: "KT"},
+			visMeters:   350,
+			temperature: ptr(4),
+			dewpoint:    ptr(4),
+			altimeter:   1025,
+			description: "fog",
+			check: func(t *testing.T, m *metar.METAR) {
+				assert.Equal(t, []metar.RunwayVisualRange{
+					{Runway: "25R", Min: 500, Max: 1500, Unit: "M", Trend: "U"},
+					{Runway: "25L", Min: 450, Max: 450, Unit: "M", Trend: "N"},
+				}, m.RVR)
+				assert.Equal(t, "BECMG 0800", m.Trend)
+			},
+		},
+		{
+			name:        "thunderstorm-speci",
+			raw:         "SPECI KDFW 182012Z 24022G38KT 2SM +TSRA SQ BKN015 OVC035CB 21/18 A2976 RMK AO2 PK WND 25045/2005 T02110183",
+			station:     "KDFW",
+			wind:        &metar.Wind{Direction: 240, Speed: 22, Gust: 38, Unit: "KT"},
+			visMeters:   3218.69,
+			temperature: ptr(21.1),
+			dewpoint:    ptr(18.3),
+			altimeter:   1007.79,
+			description: "thunderstorm with heavy rain, squalls",
+			check: func(t *testing.T, m *metar.METAR) {
+				assert.Equal(t, "SPECI", m.Type)
+			},
+		},
+		{
+			name:        "showers-in-vicinity",
+			raw:         "RJTT 180600Z 35010KT 9999 VCSH FEW020 SCT030 BKN100 22/15 Q1012 NOSIG",
+			station:     "RJTT",
+			wind:        &metar.Wind{Direction: 350, Speed: 10, Unit: "KT"},
+			visMeters:   9999,
+			temperature: ptr(22),
+			dewpoint:    ptr(15),
+			altimeter:   1012,
+			description: "showers in the vicinity",
+		},
+		{
+			name:        "freezing-fog",
+			raw:         "ESSA 180620Z 00000KT 0200 FZFG VV001 M03/M03 Q1030",
+			station:     "ESSA",
+			wind:        &metar.Wind{Speed: 0, Unit: "KT"},
+			visMeters:   200,
+			temperature: ptr(-3),
+			dewpoint:    ptr(-3),
+			altimeter:   1030,
+			description: "freezing fog",
+		},
+		{
+			name:        "clear-and-greater-than-visibility",
+			raw:         "PHNL 180853Z 06008KT P6SM CLR 24/18 A3004 RMK AO2 SLP172 T02390178",
+			station:     "PHNL",
+			wind:        &metar.Wind{Direction: 60, Speed: 8, Unit: "KT"},
+			visMeters:   9656.06,
+			temperature: ptr(23.9),
+			dewpoint:    ptr(17.8),
+			altimeter:   1017.28,
+			description: "clear sky",
+			check: func(t *testing.T, m *metar.METAR) {
+				assert.True(t, m.Visibility.GreaterThan)
+			},
+		},
+		{
+			name:        "corrected-report-with-tempo",
+			raw:         "METAR COR OMDB 181500Z 32012KT 5000 HZ NSC 34/12 Q1006 TEMPO 3000 DU",
+			station:     "OMDB",
+			wind:        &metar.Wind{Direction: 320, Speed: 12, Unit: "KT"},
+			visMeters:   5000,
+			temperature: ptr(34),
+			dewpoint:    ptr(12),
+			altimeter:   1006,
+			description: "haze",
+			check: func(t *testing.T, m *metar.METAR) {
+				assert.True(t, m.Corrected)
+				assert.Equal(t, "TEMPO 3000 DU", m.Trend)
+			},
+		},
+		{
+			name:        "missing-dewpoint",
+			raw:         "KMWN 181656Z 29045G62KT 1/16SM FZFG OVC000 M09/ A2990 RMK HEAVY RIME",
+			station:     "KMWN",
+			wind:        &metar.Wind{Direction: 290, Speed: 45, Gust: 62, Unit: "KT"},
+			visMeters:   100.58,
+			temperature: ptr(-9),
+			altimeter:   1012.53,
+			description: "freezing fog",
+		},
+		{
+			name:        "nil-report",
+			raw:         "METAR EGPN 181650Z NIL=",
+			station:     "EGPN",
+			description: "unknown",
+			check: func(t *testing.T, m *metar.METAR) {
+				assert.True(t, m.Nil)
+				assert.Nil(t, m.Temperature)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := metar.Parse(tt.raw)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.station, m.Station)
+			assert.Equal(t, 18, m.Day)
+
+			if tt.wind == nil {
+				assert.Nil(t, m.Wind)
+			} else {
+				assert.Equal(t, tt.wind, m.Wind)
+			}
+
+			if tt.visMeters == 0 {
+				assert.Nil(t, m.Visibility)
+			} else {
+				require.NotNil(t, m.Visibility)
+				assert.InDelta(t, tt.visMeters, m.Visibility.Meters(), 0.01)
+			}
+
+			assert.Equal(t, tt.temperature, m.Temperature)
+			assert.Equal(t, tt.dewpoint, m.Dewpoint)
+
+			if tt.altimeter == 0 {
+				assert.Nil(t, m.Altimeter)
+			} else {
+				require.NotNil(t, m.Altimeter)
+				assert.InDelta(t, tt.altimeter, m.Altimeter.HPa(), 0.01)
+			}
+
+			assert.Equal(t, tt.description, m.Description())
+
+			if tt.check != nil {
+				tt.check(t, m)
+			}
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, raw := range []string{"", "METAR", "KJFK", "KJFK 1817Z 31012KT", "KJFK 321751Z 31012KT"} {
+		_, err := metar.Parse(raw)
+		assert.ErrorIs(t, err, metar.ErrInvalidReport, raw)
+	}
+}
+
+func TestMETAR_ObservedAt(t *testing.T) {
+	m, err := metar.Parse("KJFK 311751Z 31012KT 10SM CLR 14/03 A3012")
+	require.NoError(t, err)
+
+	// The report is from the last day of the previous 31-day month.
+	ref := time.Date(2026, time.November, 1, 0, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2026, time.October, 31, 17, 51, 0, 0, time.UTC), m.ObservedAt(ref))
+}
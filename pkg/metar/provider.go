@@ -0,0 +1,197 @@
+package metar
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/pkg/errutil"
+	"github.com/xoltawn/weatherhub/pkg/httputil"
+	"github.com/xoltawn/weatherhub/pkg/meteo"
+)
+
+const (
+	ProviderName   = "metar"
+	DefaultBaseURL = "https://aviationweather.gov/api/data/metar"
+	// DefaultMaxAge is how old a report can be. Stations report at least hourly.
+	DefaultMaxAge = 2 * time.Hour
+)
+
+// searchRadii are the half-widths in degrees of the boxes searched for a reporting station,
+// widened until one is found.
+var searchRadii = []float64{0.5, 1.5}
+
+type metarProvider struct {
+	baseURL    string
+	geocoder   domain.Geocoder
+	httpClient *http.Client
+	userAgent  string
+	maxAge     time.Duration
+}
+
+// NewMETARProvider returns a provider reporting the latest METAR of the station nearest to the
+// city, using the aviationweather.gov data API at baseURL, or DefaultBaseURL when empty. Stations
+// whose latest report was observed longer than maxAge ago, or DefaultMaxAge when zero, are
+// passed over.
+func NewMETARProvider(baseURL string, maxAge time.Duration, geocoder domain.Geocoder, opts ...httputil.Option) domain.WeatherProvider {
+	o := httputil.NewOptions(opts...)
+
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	if maxAge <= 0 {
+		maxAge = DefaultMaxAge
+	}
+
+	return &metarProvider{
+		baseURL:    baseURL,
+		geocoder:   geocoder,
+		httpClient: o.Client(),
+		userAgent:  o.UserAgent,
+		maxAge:     maxAge,
+	}
+}
+
+// StationReport is a single entry of the aviationweather.gov JSON response.
+type StationReport struct {
+	ICAOID  string  `json:"icaoId"`
+	Lat     float64 `json:"lat"`
+	Lon     float64 `json:"lon"`
+	RawOb   string  `json:"rawOb"`
+	ObsTime int64   `json:"obsTime"`
+}
+
+func (p *metarProvider) GetForecast(ctx context.Context, city, country string, units domain.Unit) (*domain.WeatherData, error) {
+	coords, err := p.geocoder.Geocode(ctx, city, country)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := p.GetForecastByCoordinates(ctx, *coords, units)
+	if err != nil {
+		return nil, err
+	}
+
+	data.CityName = city
+	data.CountryCode = strings.ToUpper(country)
+
+	return data, nil
+}
+
+func (p *metarProvider) GetForecastByCoordinates(ctx context.Context, coords domain.Coordinates, units domain.Unit) (*domain.WeatherData, error) {
+	report, err := p.nearestReport(ctx, &coords)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := Parse(report.RawOb)
+	if err != nil {
+		log.Println(err)
+
+		return nil, errutil.Wrapf(domain.ErrThirdParty, "metar: station %s: %v", report.ICAOID, err)
+	}
+
+	data, err := toWeatherData(m, units)
+	if err != nil {
+		log.Println(err)
+
+		return nil, errutil.Wrapf(err, "metar: station %s", report.ICAOID)
+	}
+	data.Coord = &coords
+
+	return data, nil
+}
+
+// nearestReport searches boxes of growing size around coords and returns the report of the
+// closest station in the first box that has any recent enough. Stations that stopped reporting
+// are passed over, since their last report isn't the current weather.
+func (p *metarProvider) nearestReport(ctx context.Context, coords *domain.Coordinates) (*StationReport, error) {
+	observedSince := time.Now().Add(-p.maxAge).Unix()
+
+	for _, radius := range searchRadii {
+		q := url.Values{}
+		q.Set("bbox", fmt.Sprintf("%.4f,%.4f,%.4f,%.4f",
+			coords.Lat-radius, coords.Lon-radius, coords.Lat+radius, coords.Lon+radius))
+		q.Set("format", "json")
+
+		var reports []StationReport
+		if err := httputil.GetJSON(ctx, p.httpClient, p.baseURL+"?"+q.Encode(), httputil.JSONHeader(p.userAgent), &reports); err != nil {
+			log.Println(err)
+
+			return nil, err
+		}
+
+		var nearest *StationReport
+		best := math.Inf(1)
+		for i := range reports {
+			if reports[i].RawOb == "" || reports[i].ObsTime < observedSince {
+				continue
+			}
+			if d := distanceKM(coords.Lat, coords.Lon, reports[i].Lat, reports[i].Lon); d < best {
+				best, nearest = d, &reports[i]
+			}
+		}
+		if nearest != nil {
+			return nearest, nil
+		}
+	}
+
+	return nil, errutil.Wrapf(domain.ErrThirdParty, "metar: no station near %.4f,%.4f reported within %s", coords.Lat, coords.Lon, p.maxAge)
+}
+
+// toWeatherData converts a report to the requested units. Humidity is derived from temperature and
+// dewpoint, since METARs don't report it. A missing value is an error rather than a zero.
+func toWeatherData(m *METAR, units domain.Unit) (*domain.WeatherData, error) {
+	var missing []string
+	if m.Nil {
+		return nil, errutil.Wrap(domain.ErrThirdParty, "NIL report")
+	}
+	if m.Temperature == nil {
+		missing = append(missing, "temperature")
+	}
+	if m.Dewpoint == nil {
+		missing = append(missing, "dewpoint")
+	}
+	if m.Wind == nil {
+		missing = append(missing, "wind")
+	}
+	if len(missing) > 0 {
+		return nil, errutil.Wrapf(domain.ErrThirdParty, "report has no %s", strings.Join(missing, ", "))
+	}
+
+	humidity := meteo.RelativeHumidity(*m.Temperature, *m.Dewpoint)
+
+	temp, wind := *m.Temperature, m.Wind.SpeedMS()
+	if units == domain.Imperial {
+		temp = meteo.CelsiusToFahrenheit(temp)
+		wind = meteo.MSToMPH(wind)
+	}
+
+	return &domain.WeatherData{
+		Temperature: meteo.Round(temp, 2),
+		Humidity:    int(math.Round(humidity)),
+		WindSpeed:   meteo.Round(wind, 2),
+		Description: m.Description(),
+		Provider:    ProviderName,
+	}, nil
+}
+
+// distanceKM returns the great-circle distance between two points using the haversine formula.
+func distanceKM(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadiusKM = 6371.0
+
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadiusKM * math.Asin(math.Sqrt(a))
+}
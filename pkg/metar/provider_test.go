@@ -0,0 +1,173 @@
+package metar_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/pkg/metar"
+)
+
+type staticGeocoder struct {
+	coords domain.Coordinates
+}
+
+func (g staticGeocoder) Geocode(ctx context.Context, city, country string) (*domain.Coordinates, error) {
+	return &g.coords, nil
+}
+
+var london = staticGeocoder{coords: domain.Coordinates{Lat: 51.5085, Lon: -0.1257}}
+
+// fixtureObsTime is when the reports in the fixtures were observed.
+const fixtureObsTime = "1792342200"
+
+// readFixture returns a fixture whose reports were observed at obsTime.
+func readFixture(t *testing.T, name string, obsTime time.Time) []byte {
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+
+	return bytes.ReplaceAll(body, []byte(fixtureObsTime), []byte(strconv.FormatInt(obsTime.Unix(), 10)))
+}
+
+// newServer serves the fixtures in order, one per request, and an empty list once they run out.
+// Their reports are served as just observed.
+func newServer(t *testing.T, fixtures ...string) (*httptest.Server, *[]string) {
+	var bboxes []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "json", r.URL.Query().Get("format"))
+		bboxes = append(bboxes, r.URL.Query().Get("bbox"))
+
+		w.Header().Set("Content-Type", "application/json")
+
+		n := len(bboxes) - 1
+		if n >= len(fixtures) || fixtures[n] == "" {
+			w.Write([]byte("[]"))
+			return
+		}
+
+		w.Write(readFixture(t, fixtures[n], time.Now()))
+	}))
+
+	return srv, &bboxes
+}
+
+func TestMETARProvider_GetForecast(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("nearest-station", func(t *testing.T) {
+		// Arrange
+		srv, bboxes := newServer(t, "bbox_london.json")
+		defer srv.Close()
+
+		p := metar.NewMETARProvider(srv.URL, 0, london)
+
+		// Act
+		data, err := p.GetForecast(ctx, "london", "gb", domain.Metric)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, &domain.WeatherData{
+			Temperature: 15,
+			Humidity:    67,
+			WindSpeed:   5.14,
+			Description: "few clouds",
+			CityName:    "london",
+			CountryCode: "GB",
+			Provider:    metar.ProviderName,
+			Coord:       &london.coords,
+		}, data)
+		assert.Equal(t, []string{"51.0085,-0.6257,52.0085,0.3743"}, *bboxes)
+	})
+
+	t.Run("imperial", func(t *testing.T) {
+		srv, _ := newServer(t, "bbox_london.json")
+		defer srv.Close()
+
+		p := metar.NewMETARProvider(srv.URL, 0, london)
+
+		data, err := p.GetForecast(ctx, "london", "gb", domain.Imperial)
+
+		require.NoError(t, err)
+		assert.Equal(t, 59.0, data.Temperature)
+		assert.Equal(t, 11.51, data.WindSpeed)
+	})
+
+	t.Run("search-is-widened", func(t *testing.T) {
+		srv, bboxes := newServer(t, "", "bbox_london.json")
+		defer srv.Close()
+
+		p := metar.NewMETARProvider(srv.URL, 0, london)
+
+		_, err := p.GetForecast(ctx, "london", "gb", domain.Metric)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"51.0085,-0.6257,52.0085,0.3743", "50.0085,-1.6257,53.0085,1.3743"}, *bboxes)
+	})
+
+	t.Run("no-station", func(t *testing.T) {
+		srv, bboxes := newServer(t)
+		defer srv.Close()
+
+		p := metar.NewMETARProvider(srv.URL, 0, london)
+
+		_, err := p.GetForecast(ctx, "london", "gb", domain.Metric)
+
+		assert.ErrorIs(t, err, domain.ErrThirdParty)
+		assert.Len(t, *bboxes, 2)
+	})
+
+	t.Run("stale-reports-are-passed-over", func(t *testing.T) {
+		var requests int
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(readFixture(t, "bbox_london.json", time.Now().Add(-3*time.Hour)))
+		}))
+		defer srv.Close()
+
+		p := metar.NewMETARProvider(srv.URL, 2*time.Hour, london)
+
+		_, err := p.GetForecast(ctx, "london", "gb", domain.Metric)
+
+		assert.ErrorIs(t, err, domain.ErrThirdParty)
+		assert.ErrorContains(t, err, "reported within 2h0m0s")
+		assert.Equal(t, 2, requests)
+	})
+
+	t.Run("nil-report-is-an-error", func(t *testing.T) {
+		srv, _ := newServer(t, "bbox_nil.json")
+		defer srv.Close()
+
+		p := metar.NewMETARProvider(srv.URL, 0, staticGeocoder{coords: domain.Coordinates{Lat: 56.462, Lon: -2.9707}})
+
+		_, err := p.GetForecast(ctx, "dundee", "gb", domain.Metric)
+
+		assert.ErrorIs(t, err, domain.ErrThirdParty)
+		assert.ErrorContains(t, err, "EGPN")
+	})
+
+	t.Run("upstream-error", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer srv.Close()
+
+		p := metar.NewMETARProvider(srv.URL, 0, london)
+
+		_, err := p.GetForecast(ctx, "london", "gb", domain.Metric)
+
+		var upstream *domain.UpstreamError
+		require.ErrorAs(t, err, &upstream)
+		assert.Equal(t, http.StatusBadGateway, upstream.StatusCode)
+	})
+}
@@ -0,0 +1,55 @@
+package meteo
+
+import "math"
+
+const (
+	kmhPerMS   = 3.6
+	msPerKnot  = 0.514444
+	mphPerMS   = 2.236936
+	hPaPerInHg = 33.8639
+	mPerMile   = 1609.344
+)
+
+func CelsiusToFahrenheit(c float64) float64 {
+	return c*9/5 + 32
+}
+
+func FahrenheitToCelsius(f float64) float64 {
+	return (f - 32) * 5 / 9
+}
+
+func KMHToMS(kmh float64) float64 {
+	return kmh / kmhPerMS
+}
+
+func KnotsToMS(kn float64) float64 {
+	return kn * msPerKnot
+}
+
+func MSToMPH(ms float64) float64 {
+	return ms * mphPerMS
+}
+
+func StatuteMilesToMeters(mi float64) float64 {
+	return mi * mPerMile
+}
+
+func InHgToHPa(inHg float64) float64 {
+	return inHg * hPaPerInHg
+}
+
+// RelativeHumidity derives relative humidity in percent from temperature and dewpoint
+// using the Magnus approximation.
+func RelativeHumidity(tempC, dewpointC float64) float64 {
+	const b, c = 17.625, 243.04
+
+	rh := 100 * math.Exp(b*dewpointC/(c+dewpointC)-b*tempC/(c+tempC))
+
+	return math.Min(100, math.Max(0, rh))
+}
+
+// Round rounds v to the given number of decimals.
+func Round(v float64, decimals int) float64 {
+	pow := math.Pow(10, float64(decimals))
+	return math.Round(v*pow) / pow
+}
@@ -0,0 +1,176 @@
+package nws
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/pkg/errutil"
+	"github.com/xoltawn/weatherhub/pkg/httputil"
+)
+
+const (
+	ProviderName   = "nws"
+	DefaultBaseURL = "https://api.weather.gov"
+)
+
+// supportedCountries are the ISO 3166 codes covered by the National Weather Service.
+var supportedCountries = []string{"us", "pr", "gu", "vi", "as", "mp"}
+
+type nwsProvider struct {
+	baseURL    string
+	geocoder   domain.Geocoder
+	httpClient *http.Client
+	userAgent  string
+
+	// stations caches the nearest observation station per grid point lookup.
+	stations sync.Map
+}
+
+// NewNWSProvider returns a provider for api.weather.gov, or baseURL when not empty.
+// The geocoder resolves city names, since the NWS API only works with coordinates. The API
+// requires a User-Agent identifying the application and ideally a contact address, which
+// httputil.WithUserAgent sets.
+func NewNWSProvider(baseURL string, geocoder domain.Geocoder, opts ...httputil.Option) domain.WeatherProvider {
+	o := httputil.NewOptions(opts...)
+
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	return &nwsProvider{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		geocoder:   geocoder,
+		httpClient: o.Client(),
+		userAgent:  o.UserAgent,
+	}
+}
+
+type PointResponse struct {
+	Properties struct {
+		GridID string `json:"gridId"`
+		GridX  int    `json:"gridX"`
+		GridY  int    `json:"gridY"`
+	} `json:"properties"`
+}
+
+type StationsResponse struct {
+	Features []struct {
+		Properties struct {
+			StationIdentifier string `json:"stationIdentifier"`
+			Name              string `json:"name"`
+		} `json:"properties"`
+	} `json:"features"`
+}
+
+type ObservationResponse struct {
+	Properties Observation `json:"properties"`
+}
+
+type Observation struct {
+	Timestamp        time.Time         `json:"timestamp"`
+	TextDescription  string            `json:"textDescription"`
+	Temperature      QuantitativeValue `json:"temperature"`
+	Dewpoint         QuantitativeValue `json:"dewpoint"`
+	WindSpeed        QuantitativeValue `json:"windSpeed"`
+	RelativeHumidity QuantitativeValue `json:"relativeHumidity"`
+}
+
+func (p *nwsProvider) GetForecast(ctx context.Context, city, country string, units domain.Unit) (*domain.WeatherData, error) {
+	if !slices.Contains(supportedCountries, strings.ToLower(country)) {
+		return nil, errutil.Wrapf(domain.ErrThirdParty, "nws: country %q is outside NWS coverage", country)
+	}
+
+	coords, err := p.geocoder.Geocode(ctx, city, country)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := p.GetForecastByCoordinates(ctx, *coords, units)
+	if err != nil {
+		return nil, err
+	}
+
+	data.CityName = city
+	data.CountryCode = strings.ToUpper(country)
+
+	return data, nil
+}
+
+// GetForecastByCoordinates reports the latest observation of the station nearest to coords.
+// Points outside NWS coverage fail at the grid point lookup with a 404.
+func (p *nwsProvider) GetForecastByCoordinates(ctx context.Context, coords domain.Coordinates, units domain.Unit) (*domain.WeatherData, error) {
+	station, err := p.nearestStation(ctx, &coords)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw ObservationResponse
+	if err := httputil.GetJSON(ctx, p.httpClient, p.baseURL+"/stations/"+station+"/observations/latest", p.header(), &raw); err != nil {
+		log.Println(err)
+
+		return nil, err
+	}
+
+	data, err := toWeatherData(raw.Properties, units)
+	if err != nil {
+		log.Println(err)
+
+		return nil, errutil.Wrapf(err, "nws: station %s", station)
+	}
+	data.Coord = &coords
+
+	return data, nil
+}
+
+// nearestStation resolves coordinates to a grid point and returns the first station of its
+// observation station list, which the API orders by distance.
+func (p *nwsProvider) nearestStation(ctx context.Context, coords *domain.Coordinates) (string, error) {
+	point := fmt.Sprintf("%.4f,%.4f", coords.Lat, coords.Lon)
+	if cached, ok := p.stations.Load(point); ok {
+		return cached.(string), nil
+	}
+
+	var pointResp PointResponse
+	if err := httputil.GetJSON(ctx, p.httpClient, p.baseURL+"/points/"+point, p.header(), &pointResp); err != nil {
+		log.Println(err)
+
+		return "", err
+	}
+
+	grid := pointResp.Properties
+	if grid.GridID == "" {
+		return "", errutil.Wrapf(domain.ErrThirdParty, "nws: no grid point for %s", point)
+	}
+
+	stationsURL := fmt.Sprintf("%s/gridpoints/%s/%d,%d/stations", p.baseURL, grid.GridID, grid.GridX, grid.GridY)
+
+	var stationsResp StationsResponse
+	if err := httputil.GetJSON(ctx, p.httpClient, stationsURL, p.header(), &stationsResp); err != nil {
+		log.Println(err)
+
+		return "", err
+	}
+
+	if len(stationsResp.Features) == 0 || stationsResp.Features[0].Properties.StationIdentifier == "" {
+		return "", errutil.Wrapf(domain.ErrThirdParty, "nws: no observation station near %s", point)
+	}
+
+	station := stationsResp.Features[0].Properties.StationIdentifier
+	p.stations.Store(point, station)
+
+	return station, nil
+}
+
+func (p *nwsProvider) header() http.Header {
+	header := httputil.JSONHeader(p.userAgent)
+	header.Set("Accept", "application/geo+json")
+
+	return header
+}
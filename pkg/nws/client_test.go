@@ -0,0 +1,158 @@
+package nws_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/pkg/nws"
+)
+
+type staticGeocoder struct {
+	calls int
+}
+
+func (g *staticGeocoder) Geocode(ctx context.Context, city, country string) (*domain.Coordinates, error) {
+	g.calls++
+	return &domain.Coordinates{Lat: 40.7143, Lon: -74.006}, nil
+}
+
+func newServer(t *testing.T, observation string) (*httptest.Server, *[]string) {
+	var paths []string
+
+	fixtures := map[string]string{
+		"/points/40.7143,-74.0060":           "points.json",
+		"/gridpoints/OKX/33,35/stations":     "stations.json",
+		"/stations/KNYC/observations/latest": observation,
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		assert.NotEmpty(t, r.Header.Get("User-Agent"))
+
+		fixture, ok := fixtures[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		body, err := os.ReadFile(filepath.Join("testdata", fixture))
+		require.NoError(t, err)
+
+		w.Header().Set("Content-Type", "application/geo+json")
+		w.Write(body)
+	}))
+
+	return srv, &paths
+}
+
+func TestNWSProvider_GetForecast(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("metric", func(t *testing.T) {
+		// Arrange
+		srv, _ := newServer(t, "observation_complete.json")
+		defer srv.Close()
+
+		p := nws.NewNWSProvider(srv.URL, &staticGeocoder{})
+
+		// Act
+		data, err := p.GetForecast(ctx, "new york", "us", domain.Metric)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, &domain.WeatherData{
+			Temperature: 12.2,
+			Humidity:    64,
+			WindSpeed:   4.1,
+			Description: "mostly cloudy",
+			CityName:    "new york",
+			CountryCode: "US",
+			Provider:    nws.ProviderName,
+			Coord:       &domain.Coordinates{Lat: 40.7143, Lon: -74.006},
+		}, data)
+	})
+
+	t.Run("imperial", func(t *testing.T) {
+		srv, _ := newServer(t, "observation_complete.json")
+		defer srv.Close()
+
+		p := nws.NewNWSProvider(srv.URL, &staticGeocoder{})
+
+		data, err := p.GetForecast(ctx, "new york", "us", domain.Imperial)
+
+		require.NoError(t, err)
+		assert.Equal(t, 53.96, data.Temperature)
+		assert.Equal(t, 9.17, data.WindSpeed)
+	})
+
+	t.Run("humidity-derived-from-dewpoint", func(t *testing.T) {
+		srv, _ := newServer(t, "observation_no_humidity.json")
+		defer srv.Close()
+
+		p := nws.NewNWSProvider(srv.URL, &staticGeocoder{})
+
+		data, err := p.GetForecast(ctx, "new york", "us", domain.Metric)
+
+		require.NoError(t, err)
+		assert.Equal(t, 53, data.Humidity)
+		assert.Equal(t, 0.0, data.WindSpeed)
+	})
+
+	t.Run("missing-values-are-an-error", func(t *testing.T) {
+		srv, _ := newServer(t, "observation_missing.json")
+		defer srv.Close()
+
+		p := nws.NewNWSProvider(srv.URL, &staticGeocoder{})
+
+		_, err := p.GetForecast(ctx, "new york", "us", domain.Metric)
+
+		assert.ErrorIs(t, err, domain.ErrThirdParty)
+		assert.ErrorContains(t, err, "temperature, windSpeed, relativeHumidity")
+	})
+
+	t.Run("station-is-cached", func(t *testing.T) {
+		srv, paths := newServer(t, "observation_complete.json")
+		defer srv.Close()
+
+		p := nws.NewNWSProvider(srv.URL, &staticGeocoder{})
+
+		_, err := p.GetForecast(ctx, "new york", "us", domain.Metric)
+		require.NoError(t, err)
+		_, err = p.GetForecast(ctx, "new york", "us", domain.Metric)
+		require.NoError(t, err)
+
+		assert.Len(t, *paths, 4)
+	})
+
+	t.Run("coordinates", func(t *testing.T) {
+		srv, _ := newServer(t, "observation_complete.json")
+		defer srv.Close()
+
+		geocoder := &staticGeocoder{}
+		p := nws.NewNWSProvider(srv.URL, geocoder)
+
+		data, err := p.GetForecastByCoordinates(ctx, domain.Coordinates{Lat: 40.7143, Lon: -74.006}, domain.Metric)
+
+		require.NoError(t, err)
+		assert.Equal(t, 12.2, data.Temperature)
+		assert.Empty(t, data.CityName)
+		assert.Zero(t, geocoder.calls)
+	})
+
+	t.Run("outside-coverage", func(t *testing.T) {
+		geocoder := &staticGeocoder{}
+		p := nws.NewNWSProvider("http://unused.invalid", geocoder)
+
+		_, err := p.GetForecast(ctx, "london", "gb", domain.Metric)
+
+		assert.ErrorIs(t, err, domain.ErrThirdParty)
+		assert.Zero(t, geocoder.calls)
+	})
+}
@@ -0,0 +1,114 @@
+package nws
+
+import (
+	"math"
+	"strings"
+
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/pkg/errutil"
+	"github.com/xoltawn/weatherhub/pkg/meteo"
+)
+
+// QuantitativeValue is a measurement as reported by the NWS API. Value is nil when the station
+// didn't report it, which is common.
+type QuantitativeValue struct {
+	UnitCode       string   `json:"unitCode"`
+	Value          *float64 `json:"value"`
+	QualityControl string   `json:"qualityControl"`
+}
+
+// rejectedQC are the MADIS quality control flags marking a value as unusable:
+// X failed a check, Q questioned, B subjectively bad.
+var rejectedQC = map[string]bool{"X": true, "Q": true, "B": true}
+
+// Valid reports whether the value is present and wasn't rejected by quality control.
+func (q QuantitativeValue) Valid() bool {
+	return q.Value != nil && !rejectedQC[q.QualityControl]
+}
+
+func (q QuantitativeValue) celsius() (float64, bool) {
+	if !q.Valid() {
+		return 0, false
+	}
+
+	switch q.UnitCode {
+	case "wmoUnit:degC":
+		return *q.Value, true
+	case "wmoUnit:degF":
+		return meteo.FahrenheitToCelsius(*q.Value), true
+	case "wmoUnit:K":
+		return *q.Value - 273.15, true
+	default:
+		return 0, false
+	}
+}
+
+func (q QuantitativeValue) metersPerSecond() (float64, bool) {
+	if !q.Valid() {
+		return 0, false
+	}
+
+	switch q.UnitCode {
+	case "wmoUnit:m_s-1":
+		return *q.Value, true
+	case "wmoUnit:km_h-1":
+		return meteo.KMHToMS(*q.Value), true
+	case "wmoUnit:kn":
+		return meteo.KnotsToMS(*q.Value), true
+	default:
+		return 0, false
+	}
+}
+
+func (q QuantitativeValue) percent() (float64, bool) {
+	if !q.Valid() || q.UnitCode != "wmoUnit:percent" {
+		return 0, false
+	}
+
+	return *q.Value, true
+}
+
+// toWeatherData converts an observation to the requested units. A missing or rejected relative
+// humidity is derived from temperature and dewpoint; any other missing value is an error rather
+// than a zero.
+func toWeatherData(obs Observation, units domain.Unit) (*domain.WeatherData, error) {
+	var missing []string
+
+	tempC, tempOK := obs.Temperature.celsius()
+	if !tempOK {
+		missing = append(missing, "temperature")
+	}
+
+	windMS, ok := obs.WindSpeed.metersPerSecond()
+	if !ok {
+		missing = append(missing, "windSpeed")
+	}
+
+	humidity, ok := obs.RelativeHumidity.percent()
+	if !ok {
+		dewpointC, dewOK := obs.Dewpoint.celsius()
+		if dewOK && tempOK {
+			humidity = meteo.RelativeHumidity(tempC, dewpointC)
+		} else {
+			missing = append(missing, "relativeHumidity")
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, errutil.Wrapf(domain.ErrThirdParty, "observation has no valid %s", strings.Join(missing, ", "))
+	}
+
+	temp, wind := tempC, windMS
+	if units == domain.Imperial {
+		temp = meteo.CelsiusToFahrenheit(tempC)
+		wind = meteo.MSToMPH(windMS)
+	}
+
+	return &domain.WeatherData{
+		Temperature: meteo.Round(temp, 2),
+		Humidity:    int(math.Round(humidity)),
+		WindSpeed:   meteo.Round(wind, 2),
+		Description: strings.ToLower(obs.TextDescription),
+		Provider:    ProviderName,
+	}, nil
+}
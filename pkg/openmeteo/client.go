@@ -0,0 +1,119 @@
+package openmeteo
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/pkg/errutil"
+	"github.com/xoltawn/weatherhub/pkg/httputil"
+)
+
+const (
+	ProviderName       = "openmeteo"
+	DefaultForecastURL = "https://api.open-meteo.com/v1/forecast"
+)
+
+type openMeteoProvider struct {
+	forecastURL string
+	geocoder    *Geocoder
+	validator   *validator.Validate
+	httpClient  *http.Client
+	userAgent   string
+}
+
+// NewOpenMeteoProvider returns a provider for the keyless Open-Meteo API. Empty URLs fall back to
+// DefaultForecastURL and DefaultGeocodingURL.
+func NewOpenMeteoProvider(forecastURL, geocodingURL string, validator *validator.Validate, opts ...httputil.Option) domain.WeatherProvider {
+	o := httputil.NewOptions(opts...)
+	httpClient := o.Client()
+
+	if forecastURL == "" {
+		forecastURL = DefaultForecastURL
+	}
+
+	// The geocoder shares the client. slices.Concat copies opts, so the caller's slice is left alone.
+	geocoderOpts := slices.Concat(opts, []httputil.Option{httputil.WithHTTPClient(httpClient)})
+
+	return &openMeteoProvider{
+		forecastURL: forecastURL,
+		geocoder:    NewGeocoder(geocodingURL, validator, geocoderOpts...),
+		validator:   validator,
+		httpClient:  httpClient,
+		userAgent:   o.UserAgent,
+	}
+}
+
+type ForecastResponse struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Current   struct {
+		Time             string   `json:"time"`
+		Temperature      *float64 `json:"temperature_2m" validate:"required"`
+		RelativeHumidity *int     `json:"relative_humidity_2m" validate:"required,gte=0,lte=100"`
+		WindSpeed        *float64 `json:"wind_speed_10m" validate:"required,gte=0"`
+		WeatherCode      *int     `json:"weather_code" validate:"required"`
+	} `json:"current"`
+}
+
+func (p *openMeteoProvider) GetForecast(ctx context.Context, city, country string, units domain.Unit) (*domain.WeatherData, error) {
+	loc, err := p.geocoder.Lookup(ctx, city, country)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := p.GetForecastByCoordinates(ctx, domain.Coordinates{Lat: loc.Latitude, Lon: loc.Longitude}, units)
+	if err != nil {
+		return nil, err
+	}
+
+	data.CityName = loc.Name
+	data.CountryCode = loc.CountryCode
+	data.ProviderCityID = strconv.FormatInt(loc.ID, 10)
+	data.Timezone = loc.Timezone
+
+	return data, nil
+}
+
+func (p *openMeteoProvider) GetForecastByCoordinates(ctx context.Context, coords domain.Coordinates, units domain.Unit) (*domain.WeatherData, error) {
+	params := url.Values{}
+	params.Set("latitude", fmt.Sprintf("%.4f", coords.Lat))
+	params.Set("longitude", fmt.Sprintf("%.4f", coords.Lon))
+	params.Set("current", "temperature_2m,relative_humidity_2m,wind_speed_10m,weather_code")
+	// Match OpenWeatherMap: m/s for metric, mph for imperial.
+	if units == domain.Imperial {
+		params.Set("temperature_unit", "fahrenheit")
+		params.Set("wind_speed_unit", "mph")
+	} else {
+		params.Set("temperature_unit", "celsius")
+		params.Set("wind_speed_unit", "ms")
+	}
+
+	var raw ForecastResponse
+	if err := httputil.GetJSON(ctx, p.httpClient, p.forecastURL+"?"+params.Encode(), httputil.JSONHeader(p.userAgent), &raw); err != nil {
+		log.Println(err)
+
+		return nil, err
+	}
+
+	if err := p.validator.Struct(raw); err != nil {
+		log.Println(err)
+
+		return nil, errutil.Wrap(domain.ErrThirdParty, "openmeteo: provider returned invalid schema")
+	}
+
+	return &domain.WeatherData{
+		Temperature: *raw.Current.Temperature,
+		Humidity:    *raw.Current.RelativeHumidity,
+		WindSpeed:   *raw.Current.WindSpeed,
+		Description: DescribeWMOCode(*raw.Current.WeatherCode),
+		Provider:    ProviderName,
+		Coord:       &coords,
+	}, nil
+}
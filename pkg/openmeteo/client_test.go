@@ -0,0 +1,155 @@
+package openmeteo_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/pkg/openmeteo"
+)
+
+// recordedServer replays responses from testdata. The geocoding and forecast fixtures to serve
+// are picked by the test; every request is captured for assertions.
+type recordedServer struct {
+	t         *testing.T
+	geocoding string
+	forecast  string
+	requests  []*url.URL
+}
+
+func (s *recordedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.requests = append(s.requests, r.URL)
+
+	fixture := s.forecast
+	if r.URL.Path == "/geocoding" {
+		fixture = s.geocoding
+	}
+
+	body, err := os.ReadFile(filepath.Join("testdata", fixture))
+	require.NoError(s.t, err)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+func newProvider(t *testing.T, rec *recordedServer) (domain.WeatherProvider, func()) {
+	srv := httptest.NewServer(rec)
+
+	p := openmeteo.NewOpenMeteoProvider(srv.URL+"/forecast", srv.URL+"/geocoding", validator.New())
+
+	return p, srv.Close
+}
+
+func TestOpenMeteoProvider_GetForecast(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("metric", func(t *testing.T) {
+		// Arrange
+		rec := &recordedServer{t: t, geocoding: "geocoding_london_gb.json", forecast: "forecast_london_metric.json"}
+		p, closeFn := newProvider(t, rec)
+		defer closeFn()
+
+		// Act
+		data, err := p.GetForecast(ctx, "london", "gb", domain.Metric)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, &domain.WeatherData{
+			Temperature:    14.2,
+			Humidity:       77,
+			WindSpeed:      4.61,
+			Description:    "overcast",
+			CityName:       "London",
+			CountryCode:    "GB",
+			Provider:       openmeteo.ProviderName,
+			Coord:          &domain.Coordinates{Lat: 51.50853, Lon: -0.12574},
+			ProviderCityID: "2643743",
+			Timezone:       "Europe/London",
+		}, data)
+
+		require.Len(t, rec.requests, 2)
+		assert.Equal(t, "london", rec.requests[0].Query().Get("name"))
+		assert.Equal(t, "GB", rec.requests[0].Query().Get("countryCode"))
+		assert.Equal(t, "51.5085", rec.requests[1].Query().Get("latitude"))
+		assert.Equal(t, "-0.1257", rec.requests[1].Query().Get("longitude"))
+		assert.Equal(t, "celsius", rec.requests[1].Query().Get("temperature_unit"))
+		assert.Equal(t, "ms", rec.requests[1].Query().Get("wind_speed_unit"))
+	})
+
+	t.Run("imperial", func(t *testing.T) {
+		rec := &recordedServer{t: t, geocoding: "geocoding_london_gb.json", forecast: "forecast_london_imperial.json"}
+		p, closeFn := newProvider(t, rec)
+		defer closeFn()
+
+		data, err := p.GetForecast(ctx, "london", "gb", domain.Imperial)
+
+		require.NoError(t, err)
+		assert.Equal(t, 57.6, data.Temperature)
+		assert.Equal(t, 10.3, data.WindSpeed)
+		assert.Equal(t, "slight rain", data.Description)
+		assert.Equal(t, "fahrenheit", rec.requests[1].Query().Get("temperature_unit"))
+		assert.Equal(t, "mph", rec.requests[1].Query().Get("wind_speed_unit"))
+	})
+
+	t.Run("coordinates", func(t *testing.T) {
+		rec := &recordedServer{t: t, forecast: "forecast_london_metric.json"}
+		p, closeFn := newProvider(t, rec)
+		defer closeFn()
+
+		data, err := p.GetForecastByCoordinates(ctx, domain.Coordinates{Lat: 51.4779, Lon: -0.0015}, domain.Metric)
+
+		require.NoError(t, err)
+		assert.Empty(t, data.CityName)
+		assert.Equal(t, &domain.Coordinates{Lat: 51.4779, Lon: -0.0015}, data.Coord)
+		require.Len(t, rec.requests, 1)
+		assert.Equal(t, "51.4779", rec.requests[0].Query().Get("latitude"))
+		assert.Equal(t, "-0.0015", rec.requests[0].Query().Get("longitude"))
+	})
+
+	t.Run("geocoding-is-cached", func(t *testing.T) {
+		rec := &recordedServer{t: t, geocoding: "geocoding_london_gb.json", forecast: "forecast_london_metric.json"}
+		p, closeFn := newProvider(t, rec)
+		defer closeFn()
+
+		_, err := p.GetForecast(ctx, "london", "gb", domain.Metric)
+		require.NoError(t, err)
+		_, err = p.GetForecast(ctx, "London", "GB", domain.Metric)
+		require.NoError(t, err)
+
+		assert.Len(t, rec.requests, 3)
+	})
+
+	t.Run("unknown-city", func(t *testing.T) {
+		rec := &recordedServer{t: t, geocoding: "geocoding_empty.json"}
+		p, closeFn := newProvider(t, rec)
+		defer closeFn()
+
+		_, err := p.GetForecast(ctx, "atlantis", "gr", domain.Metric)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("missing-fields", func(t *testing.T) {
+		rec := &recordedServer{t: t, geocoding: "geocoding_london_gb.json", forecast: "forecast_missing_fields.json"}
+		p, closeFn := newProvider(t, rec)
+		defer closeFn()
+
+		_, err := p.GetForecast(ctx, "london", "gb", domain.Metric)
+
+		assert.ErrorIs(t, err, domain.ErrThirdParty)
+	})
+}
+
+func TestDescribeWMOCode(t *testing.T) {
+	assert.Equal(t, "clear sky", openmeteo.DescribeWMOCode(0))
+	assert.Equal(t, "thunderstorm with heavy hail", openmeteo.DescribeWMOCode(99))
+	assert.Equal(t, "unknown", openmeteo.DescribeWMOCode(42))
+}
@@ -0,0 +1,104 @@
+package openmeteo
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/pkg/errutil"
+	"github.com/xoltawn/weatherhub/pkg/httputil"
+)
+
+const DefaultGeocodingURL = "https://geocoding-api.open-meteo.com/v1/search"
+
+type GeocodingResponse struct {
+	Results []Location `json:"results"`
+}
+
+type Location struct {
+	ID          int64   `json:"id"`
+	Name        string  `json:"name" validate:"required"`
+	Latitude    float64 `json:"latitude" validate:"gte=-90,lte=90"`
+	Longitude   float64 `json:"longitude" validate:"gte=-180,lte=180"`
+	CountryCode string  `json:"country_code" validate:"required,iso3166_1_alpha2"`
+	Timezone    string  `json:"timezone"`
+	Population  int64   `json:"population"`
+}
+
+// Geocoder resolves city names through the Open-Meteo geocoding API. Results are cached for the
+// lifetime of the Geocoder since place coordinates don't change.
+type Geocoder struct {
+	url        string
+	validator  *validator.Validate
+	httpClient *http.Client
+	userAgent  string
+
+	locations sync.Map
+}
+
+// NewGeocoder returns a Geocoder for the given endpoint, or DefaultGeocodingURL when empty.
+func NewGeocoder(geocodingURL string, validator *validator.Validate, opts ...httputil.Option) *Geocoder {
+	o := httputil.NewOptions(opts...)
+
+	if geocodingURL == "" {
+		geocodingURL = DefaultGeocodingURL
+	}
+
+	return &Geocoder{
+		url:        geocodingURL,
+		validator:  validator,
+		httpClient: o.Client(),
+		userAgent:  o.UserAgent,
+	}
+}
+
+func (g *Geocoder) Geocode(ctx context.Context, city, country string) (*domain.Coordinates, error) {
+	loc, err := g.Lookup(ctx, city, country)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Coordinates{Lat: loc.Latitude, Lon: loc.Longitude}, nil
+}
+
+// Lookup returns the best match for a city within the given country.
+func (g *Geocoder) Lookup(ctx context.Context, city, country string) (*Location, error) {
+	cacheKey := strings.ToLower(city + "," + country)
+	if cached, ok := g.locations.Load(cacheKey); ok {
+		return cached.(*Location), nil
+	}
+
+	params := url.Values{}
+	params.Set("name", city)
+	params.Set("countryCode", strings.ToUpper(country))
+	params.Set("count", "1")
+	params.Set("language", "en")
+	params.Set("format", "json")
+
+	var raw GeocodingResponse
+	if err := httputil.GetJSON(ctx, g.httpClient, g.url+"?"+params.Encode(), httputil.JSONHeader(g.userAgent), &raw); err != nil {
+		log.Println(err)
+
+		return nil, err
+	}
+
+	if len(raw.Results) == 0 {
+		return nil, errutil.Wrapf(domain.ErrNotFound, "openmeteo: no location found for %s,%s", city, country)
+	}
+
+	loc := &raw.Results[0]
+	if err := g.validator.Struct(loc); err != nil {
+		log.Println(err)
+
+		return nil, errutil.Wrap(domain.ErrThirdParty, "openmeteo: geocoding returned invalid schema")
+	}
+
+	g.locations.Store(cacheKey, loc)
+
+	return loc, nil
+}
@@ -2,28 +2,39 @@ package openweathermap
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
 	"net/url"
+	"strconv"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/xoltawn/weatherhub/internal/domain"
 	"github.com/xoltawn/weatherhub/pkg/errutil"
+	"github.com/xoltawn/weatherhub/pkg/httputil"
+)
+
+const (
+	ProviderName = "openweathermap"
 )
 
 type openWeatherProvider struct {
-	apiKey    string
-	baseURL   string
-	validator *validator.Validate
+	apiKey     string
+	baseURL    string
+	validator  *validator.Validate
+	httpClient *http.Client
+	userAgent  string
 }
 
-func NewOpenWeatherProvider(apiKey, baseURL string, validator *validator.Validate) domain.WeatherProvider {
+func NewOpenWeatherProvider(apiKey, baseURL string, validator *validator.Validate, opts ...httputil.Option) domain.WeatherProvider {
+	o := httputil.NewOptions(opts...)
+
 	return &openWeatherProvider{
-		apiKey:    apiKey,
-		baseURL:   baseURL,
-		validator: validator,
+		apiKey:     apiKey,
+		baseURL:    baseURL,
+		validator:  validator,
+		httpClient: o.Client(),
+		userAgent:  o.UserAgent,
 	}
 }
 
@@ -37,7 +48,7 @@ type OWMResponse struct {
 		Main        string `json:"main"`
 		Description string `json:"description" validate:"required"`
 		Icon        string `json:"icon"`
-	} `json:"weather"`
+	} `json:"weather" validate:"min=1,dive"`
 	Base string `json:"base"`
 	Main struct {
 		Pressure int     `json:"pressure"`
@@ -69,49 +80,57 @@ type OWMResponse struct {
 }
 
 func (p *openWeatherProvider) GetForecast(ctx context.Context, city, country string, units domain.Unit) (*domain.WeatherData, error) {
-	query := fmt.Sprintf("%s,%s", city, country)
-	fullURL := fmt.Sprintf("%s?q=%s&appid=%s&units=%s",
-		p.baseURL,
-		url.QueryEscape(query),
-		p.apiKey,
-		units,
-	)
-
-	resp, err := http.Get(fullURL)
-	if err != nil {
-		log.Println(err)
+	params := url.Values{}
+	params.Set("q", fmt.Sprintf("%s,%s", city, country))
 
-		return nil, errutil.Wrap(domain.ErrThirdParty, err.Error())
-	}
-	defer resp.Body.Close()
+	return p.fetch(ctx, params, units)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		err := fmt.Errorf("API error: status %d", resp.StatusCode)
+func (p *openWeatherProvider) GetForecastByCoordinates(ctx context.Context, coords domain.Coordinates, units domain.Unit) (*domain.WeatherData, error) {
+	params := url.Values{}
+	params.Set("lat", strconv.FormatFloat(coords.Lat, 'f', -1, 64))
+	params.Set("lon", strconv.FormatFloat(coords.Lon, 'f', -1, 64))
 
-		log.Println(err)
+	return p.fetch(ctx, params, units)
+}
 
-		return nil, errutil.Wrap(domain.ErrThirdParty, err.Error())
-	}
+func (p *openWeatherProvider) fetch(ctx context.Context, params url.Values, units domain.Unit) (*domain.WeatherData, error) {
+	params.Set("appid", p.apiKey)
+	params.Set("units", string(units))
 
 	var raw OWMResponse
-	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+	if err := httputil.GetJSON(ctx, p.httpClient, p.baseURL+"?"+params.Encode(), httputil.JSONHeader(p.userAgent), &raw); err != nil {
 		log.Println(err)
 
-		return nil, errutil.Wrap(domain.ErrThirdParty, err.Error())
+		return nil, err
 	}
 
-	if err := p.validator.Struct(raw); err != nil {
+	// Places at sea or in unpopulated areas have no name or country; only city queries need them.
+	var err error
+	if params.Has("q") {
+		err = p.validator.Struct(raw)
+	} else {
+		err = p.validator.StructExcept(raw, "Name", "Sys.Country")
+	}
+	if err != nil {
 		log.Println(err)
 
 		return nil, errutil.Wrap(domain.ErrThirdParty, "weatherapi: provider returned invalid schema")
 	}
 
-	return &domain.WeatherData{
+	data := &domain.WeatherData{
 		Temperature: raw.Main.Temp,
 		Humidity:    raw.Main.Humidity,
 		WindSpeed:   raw.Wind.Speed,
 		Description: raw.Weather[0].Description,
 		CityName:    raw.Name,
 		CountryCode: raw.Sys.Country,
-	}, nil
+		Provider:    ProviderName,
+		Coord:       &domain.Coordinates{Lat: raw.Coord.Lat, Lon: raw.Coord.Lon},
+	}
+	if raw.ID != 0 {
+		data.ProviderCityID = strconv.Itoa(raw.ID)
+	}
+
+	return data, nil
 }
@@ -0,0 +1,119 @@
+package openweathermap_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/pkg/httputil"
+	"github.com/xoltawn/weatherhub/pkg/openweathermap"
+)
+
+func TestOpenWeatherProvider_GetForecast(t *testing.T) {
+	recorded, err := os.ReadFile("testdata/weather_london.json")
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		// Arrange
+		var gotReq *http.Request
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotReq = r
+			w.Write(recorded)
+		}))
+		defer srv.Close()
+
+		p := openweathermap.NewOpenWeatherProvider("key", srv.URL, validator.New(),
+			httputil.WithUserAgent("weatherhub-test"))
+
+		// Act
+		data, err := p.GetForecast(context.Background(), "london", "gb", domain.Metric)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "London", data.CityName)
+		assert.Equal(t, "GB", data.CountryCode)
+		assert.Equal(t, 14.32, data.Temperature)
+		assert.Equal(t, 76, data.Humidity)
+		assert.Equal(t, "broken clouds", data.Description)
+		assert.Equal(t, "2643743", data.ProviderCityID)
+		assert.Equal(t, "london,gb", gotReq.URL.Query().Get("q"))
+		assert.Equal(t, "metric", gotReq.URL.Query().Get("units"))
+		assert.Equal(t, "weatherhub-test", gotReq.Header.Get("User-Agent"))
+	})
+
+	t.Run("coordinates", func(t *testing.T) {
+		var gotReq *http.Request
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotReq = r
+			w.Write([]byte(`{"coord":{"lon":-30.5,"lat":40.25},"weather":[{"id":800,"main":"Clear","description":"clear sky"}],"main":{"temp":19.4,"humidity":70},"wind":{"speed":6.2},"sys":{},"name":"","cod":200}`))
+		}))
+		defer srv.Close()
+
+		p := openweathermap.NewOpenWeatherProvider("key", srv.URL, validator.New())
+
+		data, err := p.GetForecastByCoordinates(context.Background(), domain.Coordinates{Lat: 40.25, Lon: -30.5}, domain.Metric)
+
+		require.NoError(t, err)
+		assert.Empty(t, data.CityName)
+		assert.Equal(t, &domain.Coordinates{Lat: 40.25, Lon: -30.5}, data.Coord)
+		assert.Equal(t, "40.25", gotReq.URL.Query().Get("lat"))
+		assert.Equal(t, "-30.5", gotReq.URL.Query().Get("lon"))
+		assert.False(t, gotReq.URL.Query().Has("q"))
+	})
+
+	t.Run("upstream-error", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer srv.Close()
+
+		p := openweathermap.NewOpenWeatherProvider("key", srv.URL, validator.New())
+
+		_, err := p.GetForecast(context.Background(), "london", "gb", domain.Metric)
+
+		assert.ErrorIs(t, err, domain.ErrThirdParty)
+	})
+
+	t.Run("context-cancelled", func(t *testing.T) {
+		release := make(chan struct{})
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer srv.Close()
+		defer close(release)
+
+		p := openweathermap.NewOpenWeatherProvider("key", srv.URL, validator.New())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := p.GetForecast(ctx, "london", "gb", domain.Metric)
+
+		assert.ErrorIs(t, err, domain.ErrThirdParty)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("client-timeout", func(t *testing.T) {
+		release := make(chan struct{})
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer srv.Close()
+		defer close(release)
+
+		p := openweathermap.NewOpenWeatherProvider("key", srv.URL, validator.New(),
+			httputil.WithTimeout(50*time.Millisecond))
+
+		_, err := p.GetForecast(context.Background(), "london", "gb", domain.Metric)
+
+		assert.ErrorIs(t, err, domain.ErrThirdParty)
+	})
+}
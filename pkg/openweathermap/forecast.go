@@ -0,0 +1,118 @@
+package openweathermap
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/pkg/errutil"
+	"github.com/xoltawn/weatherhub/pkg/httputil"
+)
+
+const DefaultForecastURL = "https://api.openweathermap.org/data/2.5/forecast"
+
+type openWeatherForecastProvider struct {
+	apiKey     string
+	baseURL    string
+	validator  *validator.Validate
+	httpClient *http.Client
+	userAgent  string
+}
+
+// NewOpenWeatherForecastProvider returns a provider for the 5 day / 3 hour forecast endpoint at
+// baseURL, or DefaultForecastURL when empty.
+func NewOpenWeatherForecastProvider(apiKey, baseURL string, validator *validator.Validate, opts ...httputil.Option) domain.ForecastProvider {
+	o := httputil.NewOptions(opts...)
+
+	if baseURL == "" {
+		baseURL = DefaultForecastURL
+	}
+
+	return &openWeatherForecastProvider{
+		apiKey:     apiKey,
+		baseURL:    baseURL,
+		validator:  validator,
+		httpClient: o.Client(),
+		userAgent:  o.UserAgent,
+	}
+}
+
+type OWMForecastResponse struct {
+	Cnt  int `json:"cnt"`
+	List []struct {
+		Dt   int64 `json:"dt" validate:"required"`
+		Main struct {
+			Temp     float64 `json:"temp"`
+			Humidity int     `json:"humidity" validate:"gte=0,lte=100"`
+		} `json:"main"`
+		Weather []struct {
+			ID          int    `json:"id"`
+			Main        string `json:"main"`
+			Description string `json:"description" validate:"required"`
+		} `json:"weather" validate:"min=1,dive"`
+		Wind struct {
+			Speed float64 `json:"speed" validate:"gte=0"`
+			Deg   int     `json:"deg"`
+			Gust  float64 `json:"gust"`
+		} `json:"wind"`
+		Pop   float64 `json:"pop" validate:"gte=0,lte=1"`
+		DtTxt string  `json:"dt_txt"`
+	} `json:"list" validate:"min=1,dive"`
+	City struct {
+		ID       int    `json:"id"`
+		Name     string `json:"name" validate:"required"`
+		Country  string `json:"country" validate:"required,iso3166_1_alpha2"`
+		Timezone int    `json:"timezone"`
+	} `json:"city"`
+}
+
+func (p *openWeatherForecastProvider) GetHourlyForecast(ctx context.Context, city, country string, units domain.Unit) (*domain.ForecastData, error) {
+	query := fmt.Sprintf("%s,%s", city, country)
+	fullURL := fmt.Sprintf("%s?q=%s&appid=%s&units=%s",
+		p.baseURL,
+		url.QueryEscape(query),
+		p.apiKey,
+		units,
+	)
+
+	// The endpoint doesn't say when the forecast was computed, so the request time stands in.
+	issuedAt := time.Now().UTC()
+
+	var raw OWMForecastResponse
+	if err := httputil.GetJSON(ctx, p.httpClient, fullURL, httputil.JSONHeader(p.userAgent), &raw); err != nil {
+		log.Println(err)
+
+		return nil, err
+	}
+
+	if err := p.validator.Struct(raw); err != nil {
+		log.Println(err)
+
+		return nil, errutil.Wrap(domain.ErrThirdParty, "weatherapi: provider returned invalid forecast schema")
+	}
+
+	steps := make([]domain.ForecastStep, 0, len(raw.List))
+	for _, item := range raw.List {
+		steps = append(steps, domain.ForecastStep{
+			ValidAt:                  time.Unix(item.Dt, 0).UTC(),
+			Temperature:              item.Main.Temp,
+			Description:              item.Weather[0].Description,
+			Humidity:                 item.Main.Humidity,
+			WindSpeed:                item.Wind.Speed,
+			PrecipitationProbability: item.Pop,
+		})
+	}
+
+	return &domain.ForecastData{
+		IssuedAt:    issuedAt,
+		CityName:    raw.City.Name,
+		CountryCode: raw.City.Country,
+		Provider:    ProviderName,
+		Steps:       steps,
+	}, nil
+}
@@ -0,0 +1,68 @@
+package openweathermap_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/pkg/openweathermap"
+)
+
+func TestOpenWeatherForecastProvider_GetHourlyForecast(t *testing.T) {
+	recorded, err := os.ReadFile("testdata/forecast_london.json")
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		// Arrange
+		var gotReq *http.Request
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			gotReq = r
+			w.Write(recorded)
+		}))
+		defer srv.Close()
+
+		p := openweathermap.NewOpenWeatherForecastProvider("key", srv.URL, validator.New())
+
+		// Act
+		data, err := p.GetHourlyForecast(context.Background(), "london", "gb", domain.Imperial)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "London", data.CityName)
+		assert.Equal(t, "GB", data.CountryCode)
+		assert.Equal(t, openweathermap.ProviderName, data.Provider)
+		assert.WithinDuration(t, time.Now(), data.IssuedAt, time.Minute)
+		require.Len(t, data.Steps, 4)
+		assert.Equal(t, domain.ForecastStep{
+			ValidAt:                  time.Date(2025, time.October, 18, 15, 0, 0, 0, time.UTC),
+			Temperature:              14.05,
+			Description:              "light rain",
+			Humidity:                 78,
+			WindSpeed:                4.21,
+			PrecipitationProbability: 0.42,
+		}, data.Steps[0])
+		assert.Equal(t, time.Date(2025, time.October, 19, 0, 0, 0, 0, time.UTC), data.Steps[3].ValidAt)
+		assert.Equal(t, "london,gb", gotReq.URL.Query().Get("q"))
+		assert.Equal(t, "imperial", gotReq.URL.Query().Get("units"))
+	})
+
+	t.Run("invalid-schema", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"cod":"200","cnt":0,"list":[],"city":{"name":"London","country":"GB"}}`))
+		}))
+		defer srv.Close()
+
+		p := openweathermap.NewOpenWeatherForecastProvider("key", srv.URL, validator.New())
+
+		_, err := p.GetHourlyForecast(context.Background(), "london", "gb", domain.Metric)
+
+		assert.ErrorIs(t, err, domain.ErrThirdParty)
+	})
+}
@@ -0,0 +1,59 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/pkg/errutil"
+)
+
+type NamedProvider struct {
+	Name     string
+	Provider domain.WeatherProvider
+}
+
+type fallbackProvider struct {
+	providers []NamedProvider
+}
+
+// NewFallbackProvider tries providers in the given order and moves on to the next one whenever a
+// provider fails with domain.ErrThirdParty. Other errors are returned immediately.
+func NewFallbackProvider(providers ...NamedProvider) domain.WeatherProvider {
+	return &fallbackProvider{providers: providers}
+}
+
+func (p *fallbackProvider) GetForecast(ctx context.Context, city, country string, units domain.Unit) (*domain.WeatherData, error) {
+	return p.first(ctx, func(provider domain.WeatherProvider) (*domain.WeatherData, error) {
+		return provider.GetForecast(ctx, city, country, units)
+	})
+}
+
+func (p *fallbackProvider) GetForecastByCoordinates(ctx context.Context, coords domain.Coordinates, units domain.Unit) (*domain.WeatherData, error) {
+	return p.first(ctx, func(provider domain.WeatherProvider) (*domain.WeatherData, error) {
+		return provider.GetForecastByCoordinates(ctx, coords, units)
+	})
+}
+
+// first returns the result of the first provider for which call succeeds.
+func (p *fallbackProvider) first(ctx context.Context, call func(domain.WeatherProvider) (*domain.WeatherData, error)) (*domain.WeatherData, error) {
+	err := errutil.Wrap(domain.ErrThirdParty, "no weather provider configured")
+
+	for _, np := range p.providers {
+		var data *domain.WeatherData
+		data, err = call(np.Provider)
+		if err == nil {
+			data.Provider = np.Name
+			return data, nil
+		}
+
+		if !errors.Is(err, domain.ErrThirdParty) || ctx.Err() != nil {
+			return nil, err
+		}
+
+		log.Printf("weather provider %s failed, falling back: %v", np.Name, err)
+	}
+
+	return nil, err
+}
@@ -0,0 +1,73 @@
+package resilience_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/pkg/resilience"
+)
+
+func TestFallbackProvider(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("falls-through-on-third-party-error", func(t *testing.T) {
+		primary := &scriptedProvider{errs: []error{&domain.UpstreamError{StatusCode: http.StatusTooManyRequests}}}
+		secondary := &scriptedProvider{}
+		p := resilience.NewFallbackProvider(
+			resilience.NamedProvider{Name: "primary", Provider: primary},
+			resilience.NamedProvider{Name: "secondary", Provider: secondary},
+		)
+
+		data, err := p.GetForecast(ctx, "london", "gb", domain.Metric)
+
+		require.NoError(t, err)
+		assert.Equal(t, "secondary", data.Provider)
+		assert.Equal(t, 1, primary.calls)
+		assert.Equal(t, 1, secondary.calls)
+	})
+
+	t.Run("stops-on-other-errors", func(t *testing.T) {
+		primary := &scriptedProvider{errs: []error{domain.ErrInvalidInput}}
+		secondary := &scriptedProvider{}
+		p := resilience.NewFallbackProvider(
+			resilience.NamedProvider{Name: "primary", Provider: primary},
+			resilience.NamedProvider{Name: "secondary", Provider: secondary},
+		)
+
+		_, err := p.GetForecast(ctx, "london", "gb", domain.Metric)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		assert.Equal(t, 0, secondary.calls)
+	})
+
+	t.Run("all-failing", func(t *testing.T) {
+		down := &domain.UpstreamError{StatusCode: http.StatusServiceUnavailable}
+		p := resilience.NewFallbackProvider(
+			resilience.NamedProvider{Name: "primary", Provider: &scriptedProvider{errs: []error{down}}},
+			resilience.NamedProvider{Name: "secondary", Provider: &scriptedProvider{errs: []error{down}}},
+		)
+
+		_, err := p.GetForecast(ctx, "london", "gb", domain.Metric)
+
+		assert.ErrorIs(t, err, domain.ErrThirdParty)
+	})
+
+	t.Run("coordinates", func(t *testing.T) {
+		primary := &scriptedProvider{errs: []error{&domain.UpstreamError{StatusCode: http.StatusNotFound}}}
+		secondary := &scriptedProvider{}
+		p := resilience.NewFallbackProvider(
+			resilience.NamedProvider{Name: "primary", Provider: primary},
+			resilience.NamedProvider{Name: "secondary", Provider: secondary},
+		)
+
+		data, err := p.GetForecastByCoordinates(ctx, domain.Coordinates{Lat: 48.85, Lon: 2.35}, domain.Metric)
+
+		require.NoError(t, err)
+		assert.Equal(t, "secondary", data.Provider)
+		assert.Equal(t, &domain.Coordinates{Lat: 48.85, Lon: 2.35}, data.Coord)
+	})
+}
@@ -0,0 +1,197 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/xoltawn/weatherhub/internal/domain"
+)
+
+type RetryConfig struct {
+	// MaxAttempts is the total number of calls made for one request, including the first one.
+	MaxAttempts int
+	// BaseDelay is the backoff ceiling before the first retry; it doubles on every further retry.
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff. A Retry-After longer than MaxDelay ends the retries.
+	MaxDelay time.Duration
+}
+
+// policy is the retry and circuit breaking logic shared by the provider decorators.
+type policy struct {
+	retry   RetryConfig
+	breaker *Breaker
+}
+
+func newPolicy(retry RetryConfig, breaker *Breaker) policy {
+	if retry.MaxAttempts <= 0 {
+		retry.MaxAttempts = 3
+	}
+	if retry.BaseDelay <= 0 {
+		retry.BaseDelay = 200 * time.Millisecond
+	}
+	if retry.MaxDelay <= 0 {
+		retry.MaxDelay = 5 * time.Second
+	}
+
+	return policy{retry: retry, breaker: breaker}
+}
+
+type provider struct {
+	policy
+	next domain.WeatherProvider
+}
+
+// NewProvider decorates a provider with retries using exponential backoff and full jitter,
+// guarded by the given circuit breaker. A nil breaker disables circuit breaking.
+func NewProvider(next domain.WeatherProvider, retry RetryConfig, breaker *Breaker) domain.WeatherProvider {
+	return &provider{
+		policy: newPolicy(retry, breaker),
+		next:   next,
+	}
+}
+
+func (p *provider) GetForecast(ctx context.Context, city, country string, units domain.Unit) (*domain.WeatherData, error) {
+	var data *domain.WeatherData
+
+	err := p.do(ctx, func(ctx context.Context) error {
+		var err error
+		data, err = p.next.GetForecast(ctx, city, country, units)
+		return err
+	})
+
+	return data, err
+}
+
+func (p *provider) GetForecastByCoordinates(ctx context.Context, coords domain.Coordinates, units domain.Unit) (*domain.WeatherData, error) {
+	var data *domain.WeatherData
+
+	err := p.do(ctx, func(ctx context.Context) error {
+		var err error
+		data, err = p.next.GetForecastByCoordinates(ctx, coords, units)
+		return err
+	})
+
+	return data, err
+}
+
+type forecastProvider struct {
+	policy
+	next domain.ForecastProvider
+}
+
+// NewForecastProvider is NewProvider for forecast providers.
+func NewForecastProvider(next domain.ForecastProvider, retry RetryConfig, breaker *Breaker) domain.ForecastProvider {
+	return &forecastProvider{
+		policy: newPolicy(retry, breaker),
+		next:   next,
+	}
+}
+
+func (p *forecastProvider) GetHourlyForecast(ctx context.Context, city, country string, units domain.Unit) (*domain.ForecastData, error) {
+	var data *domain.ForecastData
+
+	err := p.do(ctx, func(ctx context.Context) error {
+		var err error
+		data, err = p.next.GetHourlyForecast(ctx, city, country, units)
+		return err
+	})
+
+	return data, err
+}
+
+func (p *policy) do(ctx context.Context, call func(context.Context) error) error {
+	var err error
+
+	for attempt := 0; attempt < p.retry.MaxAttempts; attempt++ {
+		if attempt > 0 {
+			delay, ok := p.backoff(attempt, err)
+			if !ok {
+				return err
+			}
+
+			if sleepErr := sleep(ctx, delay); sleepErr != nil {
+				return err
+			}
+		}
+
+		if p.breaker != nil {
+			if openErr := p.breaker.Allow(); openErr != nil {
+				return openErr
+			}
+		}
+
+		err = call(ctx)
+
+		retryable := err != nil && ctx.Err() == nil && IsRetryable(err)
+		p.record(ctx, err, retryable)
+
+		if !retryable {
+			return err
+		}
+	}
+
+	return err
+}
+
+// record reports the outcome of a call to the breaker. Calls abandoned by the caller say nothing
+// about the upstream, and non-transient errors such as a 404 mean the upstream is answering.
+func (p *policy) record(ctx context.Context, err error, retryable bool) {
+	if p.breaker == nil {
+		return
+	}
+
+	switch {
+	case err != nil && ctx.Err() != nil:
+		p.breaker.Release()
+	case retryable:
+		p.breaker.Failure()
+	default:
+		p.breaker.Success()
+	}
+}
+
+func (p *policy) backoff(attempt int, lastErr error) (time.Duration, bool) {
+	ceiling := p.retry.BaseDelay << (attempt - 1)
+	if ceiling <= 0 || ceiling > p.retry.MaxDelay {
+		ceiling = p.retry.MaxDelay
+	}
+	delay := rand.N(ceiling + 1)
+
+	var upstreamErr *domain.UpstreamError
+	if errors.As(lastErr, &upstreamErr) && upstreamErr.RetryAfter > 0 {
+		if upstreamErr.RetryAfter > p.retry.MaxDelay {
+			return 0, false
+		}
+		delay = max(delay, upstreamErr.RetryAfter)
+	}
+
+	return delay, true
+}
+
+// IsRetryable reports whether err is a transient upstream failure: a transport error or timeout,
+// a 5xx response, or a 429.
+func IsRetryable(err error) bool {
+	var upstreamErr *domain.UpstreamError
+	if !errors.As(err, &upstreamErr) {
+		return false
+	}
+
+	return upstreamErr.StatusCode == 0 ||
+		upstreamErr.StatusCode == http.StatusTooManyRequests ||
+		upstreamErr.StatusCode >= http.StatusInternalServerError
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,125 @@
+package resilience_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xoltawn/weatherhub/internal/domain"
+	"github.com/xoltawn/weatherhub/pkg/resilience"
+)
+
+type scriptedProvider struct {
+	errs  []error
+	calls int
+}
+
+func (p *scriptedProvider) GetForecast(ctx context.Context, city, country string, units domain.Unit) (*domain.WeatherData, error) {
+	p.calls++
+	if len(p.errs) >= p.calls && p.errs[p.calls-1] != nil {
+		return nil, p.errs[p.calls-1]
+	}
+
+	return &domain.WeatherData{CityName: city}, nil
+}
+
+func (p *scriptedProvider) GetForecastByCoordinates(ctx context.Context, coords domain.Coordinates, units domain.Unit) (*domain.WeatherData, error) {
+	data, err := p.GetForecast(ctx, "", "", units)
+	if err != nil {
+		return nil, err
+	}
+	data.Coord = &coords
+
+	return data, nil
+}
+
+var fastRetry = resilience.RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+
+func TestProvider_Retry(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("recovers-from-5xx", func(t *testing.T) {
+		next := &scriptedProvider{errs: []error{
+			&domain.UpstreamError{StatusCode: http.StatusBadGateway},
+			&domain.UpstreamError{StatusCode: http.StatusServiceUnavailable},
+		}}
+		p := resilience.NewProvider(next, fastRetry, nil)
+
+		data, err := p.GetForecast(ctx, "london", "gb", domain.Metric)
+
+		require.NoError(t, err)
+		assert.Equal(t, "london", data.CityName)
+		assert.Equal(t, 3, next.calls)
+	})
+
+	t.Run("does-not-retry-4xx", func(t *testing.T) {
+		next := &scriptedProvider{errs: []error{&domain.UpstreamError{StatusCode: http.StatusNotFound}}}
+		p := resilience.NewProvider(next, fastRetry, nil)
+
+		_, err := p.GetForecast(ctx, "atlantis", "gr", domain.Metric)
+
+		assert.ErrorIs(t, err, domain.ErrThirdParty)
+		assert.Equal(t, 1, next.calls)
+	})
+
+	t.Run("gives-up-on-long-retry-after", func(t *testing.T) {
+		next := &scriptedProvider{errs: []error{
+			&domain.UpstreamError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Hour},
+		}}
+		p := resilience.NewProvider(next, fastRetry, nil)
+
+		_, err := p.GetForecast(ctx, "london", "gb", domain.Metric)
+
+		assert.ErrorIs(t, err, domain.ErrThirdParty)
+		assert.Equal(t, 1, next.calls)
+	})
+
+	t.Run("stops-after-max-attempts", func(t *testing.T) {
+		upstreamDown := &domain.UpstreamError{Err: context.DeadlineExceeded}
+		next := &scriptedProvider{errs: []error{upstreamDown, upstreamDown, upstreamDown, upstreamDown}}
+		p := resilience.NewProvider(next, fastRetry, nil)
+
+		_, err := p.GetForecast(ctx, "london", "gb", domain.Metric)
+
+		assert.ErrorIs(t, err, domain.ErrThirdParty)
+		assert.Equal(t, 3, next.calls)
+	})
+}
+
+func TestProvider_Breaker(t *testing.T) {
+	ctx := context.Background()
+
+	var transitions []string
+	breaker := resilience.NewBreaker(resilience.BreakerConfig{
+		FailureThreshold: 2,
+		OpenTimeout:      20 * time.Millisecond,
+		OnStateChange: func(from, to resilience.State) {
+			transitions = append(transitions, from.String()+"->"+to.String())
+		},
+	})
+
+	upstreamDown := &domain.UpstreamError{StatusCode: http.StatusInternalServerError}
+	next := &scriptedProvider{errs: []error{upstreamDown, upstreamDown}}
+	p := resilience.NewProvider(next, resilience.RetryConfig{MaxAttempts: 1}, breaker)
+
+	// Two failures open the circuit.
+	p.GetForecast(ctx, "london", "gb", domain.Metric)
+	p.GetForecast(ctx, "london", "gb", domain.Metric)
+	assert.Equal(t, resilience.StateOpen, breaker.State())
+
+	// While open, calls fail fast without reaching the upstream.
+	_, err := p.GetForecast(ctx, "london", "gb", domain.Metric)
+	assert.ErrorIs(t, err, resilience.ErrCircuitOpen)
+	assert.ErrorIs(t, err, domain.ErrThirdParty)
+	assert.Equal(t, 2, next.calls)
+
+	// After the open timeout a successful probe closes it again.
+	time.Sleep(30 * time.Millisecond)
+	_, err = p.GetForecast(ctx, "london", "gb", domain.Metric)
+	require.NoError(t, err)
+	assert.Equal(t, resilience.StateClosed, breaker.State())
+	assert.Equal(t, []string{"closed->open", "open->half-open", "half-open->closed"}, transitions)
+}